@@ -0,0 +1,97 @@
+package pageboy
+
+import (
+	"errors"
+
+	pbc "github.com/soranoba/pageboy/v4/core"
+	"gorm.io/gorm"
+)
+
+// ErrAroundRequiresFindAround is an error returned when the cursor that has Around is used by Scope.
+var ErrAroundRequiresFindAround = errors.New("cursor.Around can be used only by FindAround")
+
+// AroundPage is the records around the position and the pagination information.
+// NextBefore, NextAfter, HasMoreBefore and HasMoreAfter are the same direction as Before and After of Cursor.
+type AroundPage[T any] struct {
+	Items         []T              `json:"items"`
+	NextBefore    pbc.CursorString `json:"next_before"`
+	NextAfter     pbc.CursorString `json:"next_after"`
+	HasMoreBefore bool             `json:"has_more_before"`
+	HasMoreAfter  bool             `json:"has_more_after"`
+}
+
+// FindAround finds the records around the Around of the cursor, and returns them in the order of the cursor.
+// It returns up to Limit records on each side, and the record at the position if it exists.
+//
+//	cursor := &pageboy.Cursor{Around: messageCursor, Limit: 20}
+//	page, err := pageboy.FindAround[*Message](db, cursor.Paginate("CreatedAt", "ID").Order("ASC", "ASC"))
+func FindAround[T any](db *gorm.DB, cursor *Cursor) (*AroundPage[T], error) {
+	c := cursor.clone()
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	if c.Around == "" {
+		return nil, &ValidationError{Field: "Around", Message: "is required"}
+	}
+	around := c.Around
+	c.Around = ""
+	c.fill = FillDefault
+	c.itemCursors = true
+
+	find := func(c *Cursor) ([]T, *CursorResult, error) {
+		items := make([]T, 0, c.Limit)
+		result := &CursorResult{}
+		if err := db.Session(&gorm.Session{}).Scopes(c.ScopeWithResult(result)).Find(&items).Error; err != nil {
+			return nil, nil, err
+		}
+		return items, result, nil
+	}
+
+	// the records before the position in the order of the cursor, that are read from the nearest.
+	head := c.clone()
+	head.setRange("", around)
+	head.Reverse = !head.Reverse
+	headItems, headResult, err := find(head)
+	if err != nil {
+		return nil, err
+	}
+
+	center := c.clone()
+	center.Since, center.Until, center.Limit = around, around, 1
+	centerItems, _, err := find(center)
+	if err != nil {
+		return nil, err
+	}
+
+	tail := c.clone()
+	tail.setRange(around, "")
+	tailItems, tailResult, err := find(tail)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]T, 0, len(headItems)+len(centerItems)+len(tailItems))
+	for i := len(headItems) - 1; i >= 0; i-- {
+		items = append(items, headItems[i])
+	}
+	items = append(items, centerItems...)
+	items = append(items, tailItems...)
+
+	first, last := around, around
+	if len(headItems) > 0 {
+		first = headResult.itemCursors[len(headItems)-1]
+	}
+	if len(tailItems) > 0 {
+		last = tailResult.itemCursors[len(tailItems)-1]
+	}
+
+	page := &AroundPage[T]{Items: items}
+	if c.isForward() {
+		page.NextBefore, page.NextAfter = first, last
+		page.HasMoreBefore, page.HasMoreAfter = headResult.HasMore(), tailResult.HasMore()
+	} else {
+		page.NextBefore, page.NextAfter = last, first
+		page.HasMoreBefore, page.HasMoreAfter = tailResult.HasMore(), headResult.HasMore()
+	}
+	return page, nil
+}
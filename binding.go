@@ -0,0 +1,169 @@
+package pageboy
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	pbc "github.com/soranoba/pageboy/v4/core"
+)
+
+// ParamNames are the names of query parameters that are bound to Cursor and Pager.
+// The parameters that have an empty name are not bound.
+type ParamNames struct {
+	// Cursor
+	Before  string
+	After   string
+	Until   string
+	Since   string
+	Around  string
+	Limit   string
+	Reverse string
+	// Pager
+	Page    string
+	PerPage string
+	Anchor  string
+}
+
+var (
+	// DefaultParamNames are the same names as the query tags of Cursor and Pager.
+	DefaultParamNames = ParamNames{
+		Before:  "before",
+		After:   "after",
+		Until:   "until",
+		Since:   "since",
+		Around:  "around",
+		Limit:   "limit",
+		Reverse: "reverse",
+		Page:    "page",
+		PerPage: "per_page",
+		Anchor:  "anchor",
+	}
+	// JSONAPIParamNames are the names of JSON:API.
+	// See: https://jsonapi.org/profiles/ethanresnick/cursor-pagination/
+	// `page[reverse]` is not defined by the profile, and it is used by the prev link of Cursor.
+	JSONAPIParamNames = ParamNames{
+		Before:  "page[before]",
+		After:   "page[after]",
+		Limit:   "page[size]",
+		Reverse: "page[reverse]",
+		Page:    "page[number]",
+		PerPage: "page[size]",
+	}
+)
+
+// CursorFromValues returns a Cursor that the values are bound by DefaultParamNames.
+func CursorFromValues(values url.Values) (*Cursor, error) {
+	return DefaultParamNames.CursorFromValues(values)
+}
+
+// CursorFromRequest returns a Cursor that the query of the request is bound by DefaultParamNames.
+func CursorFromRequest(r *http.Request) (*Cursor, error) {
+	return DefaultParamNames.CursorFromValues(r.URL.Query())
+}
+
+// PagerFromValues returns a Pager that the values are bound by DefaultParamNames.
+func PagerFromValues(values url.Values) (*Pager, error) {
+	return DefaultParamNames.PagerFromValues(values)
+}
+
+// PagerFromRequest returns a Pager that the query of the request is bound by DefaultParamNames.
+func PagerFromRequest(r *http.Request) (*Pager, error) {
+	return DefaultParamNames.PagerFromValues(r.URL.Query())
+}
+
+// CursorFromValues returns a Cursor that the values are bound.
+// The parameters that the values do not have are the same as NewCursor.
+// It returns a ValidationError that the field is the name of the parameter, when the value cannot be parsed.
+func (names ParamNames) CursorFromValues(values url.Values) (*Cursor, error) {
+	cursor := NewCursor()
+	if err := names.bindCursor(values, cursor); err != nil {
+		return nil, err
+	}
+	return cursor, nil
+}
+
+// CursorFromRequest returns a Cursor that the query of the request is bound.
+func (names ParamNames) CursorFromRequest(r *http.Request) (*Cursor, error) {
+	return names.CursorFromValues(r.URL.Query())
+}
+
+// PagerFromValues returns a Pager that the values are bound.
+// The parameters that the values do not have are the same as NewPager.
+// It returns a ValidationError that the field is the name of the parameter, when the value cannot be parsed.
+func (names ParamNames) PagerFromValues(values url.Values) (*Pager, error) {
+	pager := NewPager()
+	if err := names.bindPager(values, pager); err != nil {
+		return nil, err
+	}
+	return pager, nil
+}
+
+// PagerFromRequest returns a Pager that the query of the request is bound.
+func (names ParamNames) PagerFromRequest(r *http.Request) (*Pager, error) {
+	return names.PagerFromValues(r.URL.Query())
+}
+
+func (names ParamNames) bindCursor(values url.Values, cursor *Cursor) error {
+	if value, ok := lookupValue(values, names.Before); ok {
+		cursor.Before = pbc.CursorString(value)
+	}
+	if value, ok := lookupValue(values, names.After); ok {
+		cursor.After = pbc.CursorString(value)
+	}
+	if value, ok := lookupValue(values, names.Until); ok {
+		cursor.Until = pbc.CursorString(value)
+	}
+	if value, ok := lookupValue(values, names.Since); ok {
+		cursor.Since = pbc.CursorString(value)
+	}
+	if value, ok := lookupValue(values, names.Around); ok {
+		cursor.Around = pbc.CursorString(value)
+	}
+	if value, ok := lookupValue(values, names.Limit); ok {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			return &ValidationError{Field: names.Limit, Message: "must be an integer"}
+		}
+		cursor.Limit = limit
+	}
+	if value, ok := lookupValue(values, names.Reverse); ok {
+		reverse, err := strconv.ParseBool(value)
+		if err != nil {
+			return &ValidationError{Field: names.Reverse, Message: "must be a boolean"}
+		}
+		cursor.Reverse = reverse
+	}
+	return nil
+}
+
+func (names ParamNames) bindPager(values url.Values, pager *Pager) error {
+	if value, ok := lookupValue(values, names.Page); ok {
+		page, err := strconv.Atoi(value)
+		if err != nil {
+			return &ValidationError{Field: names.Page, Message: "must be an integer"}
+		}
+		pager.Page = page
+	}
+	if value, ok := lookupValue(values, names.PerPage); ok {
+		perPage, err := strconv.Atoi(value)
+		if err != nil {
+			return &ValidationError{Field: names.PerPage, Message: "must be an integer"}
+		}
+		pager.PerPage = perPage
+	}
+	if value, ok := lookupValue(values, names.Anchor); ok {
+		pager.Anchor = pbc.CursorString(value)
+	}
+	return nil
+}
+
+// lookupValue returns the first value of the name.
+// Empty values are treated as the same as the name does not exist.
+func lookupValue(values url.Values, name string) (string, bool) {
+	if name == "" {
+		return "", false
+	}
+	value := values.Get(name)
+	return value, value != ""
+}
@@ -0,0 +1,154 @@
+package pageboy
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	pbc "github.com/soranoba/pageboy/v4/core"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CheckpointStore is a storage of positions that jobs have been processed.
+// See: RunWithCheckpoint
+type CheckpointStore interface {
+	// Load returns the position saved by the job. If it does not exist, it returns an empty string.
+	Load(ctx context.Context, job string) (pbc.CursorString, error)
+	// Save saves the position of the job.
+	Save(ctx context.Context, job string, position pbc.CursorString) error
+}
+
+// RunWithCheckpoint calls fn with each batch of the records read by Iterator,
+// and saves the position to the store after fn returns nil.
+// If the position of the job has been saved, it resumes from the position.
+//
+// The saved position remains after all records are processed, so please use another job name to run again from the beginning.
+func RunWithCheckpoint[T any](
+	ctx context.Context,
+	db *gorm.DB,
+	store CheckpointStore,
+	job string,
+	cursor *Cursor,
+	fn func(batch []T) error,
+) error {
+	position, err := store.Load(ctx, job)
+	if err != nil {
+		return err
+	}
+	if position != "" {
+		if !position.Validate() {
+			return &ValidationError{Field: "Checkpoint", Message: "is invalid"}
+		}
+		cursor = cursor.next(position)
+	}
+
+	it := NewIterator[T](db, cursor)
+	for it.Next(ctx) {
+		if err := fn(it.Batch()); err != nil {
+			return err
+		}
+		if err := store.Save(ctx, job, it.Position()); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
+// FileCheckpointStore is a CheckpointStore that saves positions to files in the directory.
+type FileCheckpointStore struct {
+	Dir string
+}
+
+// Load returns the position saved by the job. If it does not exist, it returns an empty string.
+func (store *FileCheckpointStore) Load(ctx context.Context, job string) (pbc.CursorString, error) {
+	path, err := store.path(job)
+	if err != nil {
+		return "", err
+	}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	return pbc.CursorString(b), nil
+}
+
+// Save saves the position of the job.
+// It replaces the file atomically, so the position is not broken even if the process is crashed.
+func (store *FileCheckpointStore) Save(ctx context.Context, job string, position pbc.CursorString) error {
+	path, err := store.path(job)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(store.Dir, ".pageboy_*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.WriteString(string(position)); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// path returns the path of the file of the job.
+// It returns a ValidationError when the job name may point outside the directory.
+func (store *FileCheckpointStore) path(job string) (string, error) {
+	if job == "" || job == "." || strings.Contains(job, "..") || strings.ContainsAny(job, `/\`) {
+		return "", &ValidationError{Field: "Job", Message: "is invalid"}
+	}
+	return filepath.Join(store.Dir, url.PathEscape(job)), nil
+}
+
+// Checkpoint is a model of GormCheckpointStore.
+// Please migrate it before use. (e.g. db.AutoMigrate(&pageboy.Checkpoint{}))
+type Checkpoint struct {
+	Job       string `gorm:"primaryKey;size:191"`
+	Position  string `gorm:"size:1024"`
+	UpdatedAt time.Time
+}
+
+// TableName returns the table name of Checkpoint.
+func (Checkpoint) TableName() string {
+	return "pageboy_checkpoints"
+}
+
+// GormCheckpointStore is a CheckpointStore that saves positions to the table of Checkpoint.
+type GormCheckpointStore struct {
+	DB *gorm.DB
+}
+
+// Load returns the position saved by the job. If it does not exist, it returns an empty string.
+func (store *GormCheckpointStore) Load(ctx context.Context, job string) (pbc.CursorString, error) {
+	var checkpoints []Checkpoint
+	if err := store.DB.WithContext(ctx).Where("job = ?", job).Limit(1).Find(&checkpoints).Error; err != nil {
+		return "", err
+	}
+	if len(checkpoints) == 0 {
+		return "", nil
+	}
+	return pbc.CursorString(checkpoints[0].Position), nil
+}
+
+// Save saves the position of the job.
+func (store *GormCheckpointStore) Save(ctx context.Context, job string, position pbc.CursorString) error {
+	return store.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "job"}},
+		DoUpdates: clause.AssignmentColumns([]string{"position", "updated_at"}),
+	}).Create(&Checkpoint{Job: job, Position: string(position)}).Error
+}
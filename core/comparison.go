@@ -16,10 +16,37 @@ const (
 	GreaterThan Comparison = ">"
 	// LessThan means that the column value in DB record is less than the specified value.
 	LessThan Comparison = "<"
+	// GreaterThanOrEqual means that the column value in DB record is greater than or equal to the specified value.
+	GreaterThanOrEqual Comparison = ">="
+	// LessThanOrEqual means that the column value in DB record is less than or equal to the specified value.
+	LessThanOrEqual Comparison = "<="
 )
 
+// Inclusive returns the comparison that includes the specified value.
+func (c Comparison) Inclusive() Comparison {
+	switch c {
+	case GreaterThan:
+		return GreaterThanOrEqual
+	case LessThan:
+		return LessThanOrEqual
+	}
+	return c
+}
+
+// Strict returns the comparison that excludes the specified value.
+func (c Comparison) Strict() Comparison {
+	switch c {
+	case GreaterThanOrEqual:
+		return GreaterThan
+	case LessThanOrEqual:
+		return LessThan
+	}
+	return c
+}
+
 // MakeComparisonScope returns a GORM scope builder.
 // This scope add a where clauses filtered by comparisons ranges.
+// If the comparison of the last column is GreaterThanOrEqual or LessThanOrEqual, the record equal to the values is also included.
 func MakeComparisonScope(columns []string, comparisons []Comparison, nullsOrders []NullsOrder, values []interface{}) func(*gorm.DB) *gorm.DB {
 	if len(columns) != len(comparisons) {
 		panic("columns and comparisons must have the same length")
@@ -46,10 +73,17 @@ func MakeComparisonScope(columns []string, comparisons []Comparison, nullsOrders
 			if i == 0 {
 				comparisons = append(comparisons, GreaterThan)
 			} else {
-				comparisons = append(comparisons, comparisons[i-1])
+				comparisons = append(comparisons, comparisons[i-1].Strict())
 			}
 		}
 
+		// NOTE: the inclusive range is the strict range or the equality of all columns.
+		isInclusive := length > 0 && comparisons[length-1] != comparisons[length-1].Strict()
+		strictComparisons := make([]Comparison, length)
+		for i, comparison := range comparisons {
+			strictComparisons[i] = comparison.Strict()
+		}
+
 		isPostgres := (db.Dialector.Name() == "postgres")
 
 	Loop:
@@ -68,13 +102,13 @@ func MakeComparisonScope(columns []string, comparisons []Comparison, nullsOrders
 			val := reflect.ValueOf(values[i])
 			isNil := val.Kind() == reflect.Ptr && val.IsNil()
 
-			if (comparisons[i] == LessThan && nullsOrder == TreatsAsLowest) ||
-				(comparisons[i] == GreaterThan && nullsOrder == TreatsAsHighest) {
+			if (strictComparisons[i] == LessThan && nullsOrder == TreatsAsLowest) ||
+				(strictComparisons[i] == GreaterThan && nullsOrder == TreatsAsHighest) {
 				if isNil {
 					eqQuery += fmt.Sprintf("%s IS NULL AND ", column)
 					continue Loop
 				} else {
-					query := fmt.Sprintf("(%s(%s IS NULL OR %s %s ?))", eqQuery, column, column, comparisons[i])
+					query := fmt.Sprintf("(%s(%s IS NULL OR %s %s ?))", eqQuery, column, column, strictComparisons[i])
 					queries = append(queries, query)
 				}
 			} else {
@@ -82,7 +116,7 @@ func MakeComparisonScope(columns []string, comparisons []Comparison, nullsOrders
 					eqQuery += fmt.Sprintf("%s IS NOT NULL OR ", column)
 					continue Loop
 				} else {
-					query := fmt.Sprintf("(%s%s %s ?)", eqQuery, column, comparisons[i])
+					query := fmt.Sprintf("(%s%s %s ?)", eqQuery, column, strictComparisons[i])
 					queries = append(queries, query)
 				}
 			}
@@ -91,6 +125,22 @@ func MakeComparisonScope(columns []string, comparisons []Comparison, nullsOrders
 			nonNilValues = append(nonNilValues, values[i])
 			queryValues = append(queryValues, nonNilValues...)
 		}
+
+		if isInclusive {
+			eqQueries := make([]string, length)
+			for i, column := range columns[:length] {
+				column = toSnake(column)
+
+				val := reflect.ValueOf(values[i])
+				if values[i] == nil || (val.Kind() == reflect.Ptr && val.IsNil()) {
+					eqQueries[i] = fmt.Sprintf("%s IS NULL", column)
+				} else {
+					eqQueries[i] = fmt.Sprintf("%s = ?", column)
+					queryValues = append(queryValues, values[i])
+				}
+			}
+			queries = append(queries, "("+strings.Join(eqQueries, " AND ")+")")
+		}
 		return db.Where("("+strings.Join(queries, " OR ")+")", queryValues...)
 	}
 }
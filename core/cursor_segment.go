@@ -33,6 +33,11 @@ func (seg CursorSegment) Int64Ptr() *int64 {
 	return &i
 }
 
+// IsInteger returns true if it does not have the fractional part, such as the values except time.
+func (seg CursorSegment) IsInteger() bool {
+	return seg.nano == 0
+}
+
 // Bool returns converted to bool.
 func (seg CursorSegment) Bool() bool {
 	if seg.integer > 0 {
@@ -60,24 +65,28 @@ func (seg CursorSegment) Time() *time.Time {
 }
 
 // Interface returns converted to the type of the specified column.
+// If the type is not a struct, it is treated as the type of the column itself. (e.g. the result of Pluck)
 func (seg CursorSegment) Interface(ty reflect.Type, column string) interface{} {
-	if ty.Kind() != reflect.Struct {
-		panic("model must be struct")
+	if ty.Kind() != reflect.Struct || ty == reflect.TypeOf(time.Time{}) {
+		return seg.interfaceOf(ty)
 	}
 
 	field, ok := ty.FieldByName(column)
 	if !ok {
 		return seg.Int64()
 	}
+	return seg.interfaceOf(field.Type)
+}
 
-	if field.Type == reflect.TypeOf(time.Time{}) ||
-		field.Type == reflect.TypeOf(new(time.Time)) {
+func (seg CursorSegment) interfaceOf(ty reflect.Type) interface{} {
+	if ty == reflect.TypeOf(time.Time{}) ||
+		ty == reflect.TypeOf(new(time.Time)) {
 		return seg.Time()
 	}
 
-	switch field.Type.Kind() {
+	switch ty.Kind() {
 	case reflect.Ptr:
-		if field.Type.Elem().Kind() == reflect.Bool {
+		if ty.Elem().Kind() == reflect.Bool {
 			return seg.BoolPtr()
 		}
 		return seg.Int64Ptr()
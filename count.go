@@ -0,0 +1,107 @@
+package pageboy
+
+import (
+	"context"
+	"encoding/json"
+
+	"gorm.io/gorm"
+)
+
+// CountMode is how to count the records.
+type CountMode int
+
+const (
+	// CountNone does not count.
+	CountNone CountMode = iota
+	// CountExact counts all records.
+	CountExact
+	// CountEstimated uses the estimated number of rows by the query planner.
+	// It is supported only by PostgreSQL, and the others count exactly.
+	CountEstimated
+	// CountCapped counts records up to Counts.Cap.
+	CountCapped
+)
+
+// DefaultCountCap is the cap used when Counts.Cap is not specified.
+const DefaultCountCap = 1000
+
+// Counts is the configuration of counts of Cursor.
+// See: Cursor.Counts
+type Counts struct {
+	// Total is the mode to count the records of the query without the positions of the cursor.
+	Total CountMode
+	// Remaining is the mode to count the records in the range of the positions of the cursor.
+	// It includes the records returned by the query.
+	Remaining CountMode
+	// Cap is the upper bound of CountCapped. (default: DefaultCountCap)
+	Cap int64
+}
+
+// CountSummary is a count of records.
+type CountSummary struct {
+	Count int64 `json:"count"`
+	// IsEstimated is true when the Count is estimated by the query planner.
+	IsEstimated bool `json:"is_estimated,omitempty"`
+	// IsCapped is true when the count reached the cap, so the actual count is greater than the Count.
+	IsCapped bool `json:"is_capped,omitempty"`
+}
+
+// countRecords returns the count of the records of db by the mode.
+func countRecords(db *gorm.DB, mode CountMode, cap int64) (*CountSummary, error) {
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	query := newCountQuery(db, ctx, false)
+
+	switch mode {
+	case CountEstimated:
+		if db.Dialector.Name() == "postgres" {
+			count, err := estimateCount(query)
+			if err != nil {
+				return nil, err
+			}
+			return &CountSummary{Count: count, IsEstimated: true}, nil
+		}
+	case CountCapped:
+		if cap < 1 {
+			cap = DefaultCountCap
+		}
+		var count int64
+		limited := query.Select("1 AS pageboy_one").Limit(int(cap + 1))
+		if err := query.Session(&gorm.Session{NewDB: true}).Table("(?) AS pageboy_capped", limited).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if count > cap {
+			return &CountSummary{Count: cap, IsCapped: true}, nil
+		}
+		return &CountSummary{Count: count}, nil
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return nil, err
+	}
+	return &CountSummary{Count: count}, nil
+}
+
+// estimateCount returns the number of rows estimated by EXPLAIN of PostgreSQL.
+func estimateCount(query *gorm.DB) (int64, error) {
+	var plan string
+	if err := query.Session(&gorm.Session{NewDB: true}).Raw("EXPLAIN (FORMAT JSON) ?", query).Row().Scan(&plan); err != nil {
+		return 0, err
+	}
+
+	var plans []struct {
+		Plan struct {
+			PlanRows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal([]byte(plan), &plans); err != nil {
+		return 0, err
+	}
+	if len(plans) == 0 {
+		return 0, nil
+	}
+	return int64(plans[0].Plan.PlanRows), nil
+}
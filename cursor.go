@@ -1,21 +1,29 @@
 package pageboy
 
 import (
-	"bytes"
+	"math"
 	"net/url"
 	"reflect"
-	"strings"
+	"sync/atomic"
 
 	pbc "github.com/soranoba/pageboy/v4/core"
 	"gorm.io/gorm"
-	"gorm.io/gorm/clause"
 )
 
 // Cursor is a builder that build a GORM scope that specifies a range from the cursor position of records.
 // You can read it from query or json.
+//
+// The results of queries are not written to the Cursor when you use ScopeWithResult,
+// so the Cursor can be shared by multiple goroutines after the configuration.
+//
+// Until and Since are the same as Before and After, but they include the record at the position.
+// Around is the position of the center of the records, and it can be used only by FindAround.
 type Cursor struct {
 	Before  pbc.CursorString `json:"before"  query:"before"`
 	After   pbc.CursorString `json:"after"   query:"after"`
+	Until   pbc.CursorString `json:"until"   query:"until"`
+	Since   pbc.CursorString `json:"since"   query:"since"`
+	Around  pbc.CursorString `json:"around"  query:"around"`
 	Limit   int              `json:"limit"   query:"limit"`
 	Reverse bool             `json:"reverse" query:"reverse"`
 
@@ -25,14 +33,50 @@ type Cursor struct {
 	nullsOrders []pbc.NullsOrder
 	// See: cursor.Paginate
 	columns []string
+	// See: cursor.Limits
+	limits Limits
+	// See: cursor.ItemCursors
+	itemCursors bool
+	// See: cursor.TieBreaker
+	tieBreaker TieBreakerPolicy
+	// See: cursor.Fill
+	fill FillDirection
+	// See: cursor.Counts
+	counts Counts
+	// The names of the parameters that the cursor is bound by. See: ParamNames.DetectPaginator
+	paramNames *ParamNames
+
+	// The result of the last query executed with Scope. (*CursorResult)
+	last atomic.Value
+}
 
-	nextBefore pbc.CursorString
-	nextAfter  pbc.CursorString
-	baseOrder  pbc.Order
-	limit      int
-	hasMore    bool
+// cursorQuery is the state of a query paginated by Cursor.
+type cursorQuery struct {
+	cursor *Cursor
+	// keyset is the cursor that has the effective columns. See: cursor.TieBreaker
+	keyset *Cursor
+	result *CursorResult
+	limit  int
+	// isLast is true when the result is the last result of the cursor.
+	isLast bool
+	// countOnly is true when the query counts records in the range of the cursor.
+	countOnly bool
+	// isFlipped is true when the records are read in the reverse order by the fill direction.
+	isFlipped bool
 }
 
+// FillDirection is the end of the range that the records are read from.
+type FillDirection int
+
+const (
+	// FillDefault reads the records from the start of the order.
+	FillDefault FillDirection = iota
+	// FillFromAfter reads the records nearest to After (or Since).
+	FillFromAfter
+	// FillFromBefore reads the records nearest to Before (or Until).
+	FillFromBefore
+)
+
 // CursorPagingUrls is for the user to access from the next cursor position.
 // If it is no records at target of next, Next will be empty.
 type CursorPagingUrls struct {
@@ -55,47 +99,52 @@ func (cursor *Cursor) Validate() error {
 	if cursor.After != "" && !cursor.After.Validate() {
 		return &ValidationError{Field: "After", Message: "is invalid"}
 	}
+	if cursor.Until != "" && !cursor.Until.Validate() {
+		return &ValidationError{Field: "Until", Message: "is invalid"}
+	}
+	if cursor.Since != "" && !cursor.Since.Validate() {
+		return &ValidationError{Field: "Since", Message: "is invalid"}
+	}
+	if cursor.Before != "" && cursor.Until != "" {
+		return &ValidationError{Field: "Until", Message: "cannot be used with Before"}
+	}
+	if cursor.After != "" && cursor.Since != "" {
+		return &ValidationError{Field: "Since", Message: "cannot be used with After"}
+	}
+	if cursor.Around != "" {
+		if !cursor.Around.Validate() {
+			return &ValidationError{Field: "Around", Message: "is invalid"}
+		}
+		if cursor.Before != "" || cursor.After != "" || cursor.Until != "" || cursor.Since != "" {
+			return &ValidationError{Field: "Around", Message: "cannot be used with other positions"}
+		}
+	}
 	if cursor.Limit < 1 {
 		return &ValidationError{Field: "Limit", Message: "is invalid"}
 	}
-	return nil
+	// NOTE: the clamped limit is not written back, because the cursor may be shared. It is applied to each query.
+	_, err := cursor.limits.enforce("Limit", cursor.Limit, cursor.limits.MaxLimit)
+	return err
 }
 
 // GetNextAfter returns a value of query to access if it exists some records after the current position.
+// It is the result of the last query executed with Scope.
 func (cursor *Cursor) GetNextAfter() pbc.CursorString {
-	return cursor.nextAfter
+	return cursor.lastResult().GetNextAfter()
 }
 
 // GetNextBefore returns a value of query to access if it exists some records before the current position.
+// It is the result of the last query executed with Scope.
 func (cursor *Cursor) GetNextBefore() pbc.CursorString {
-	return cursor.nextBefore
+	return cursor.lastResult().GetNextBefore()
 }
 
 // BuildNextPagingUrls returns URLs for the user to access from the next cursor position.
+// It is the result of the last query executed with Scope.
 //
 // You can use GetNextBefore and GetNextAfter if you want to customize the behavior.
 func (cursor *Cursor) BuildNextPagingUrls(base *url.URL) *CursorPagingUrls {
-	pagingUrls := &CursorPagingUrls{}
-
-	if base == nil {
-		return pagingUrls
-	}
-
-	if cursor.hasMore {
-		baseURL := *base
-		query := baseURL.Query()
-		if (cursor.baseOrder == pbc.ASC) != cursor.Reverse {
-			query.Del("after")
-			query.Add("after", string(cursor.nextAfter))
-		} else {
-			query.Del("before")
-			query.Add("before", string(cursor.nextBefore))
-		}
-		baseURL.RawQuery = query.Encode()
-		pagingUrls.Next = baseURL.String()
-	}
-
-	return pagingUrls
+	return cursor.lastResult().BuildNextPagingUrls(base)
 }
 
 // Paginate set the pagination target columns, and returns self.
@@ -107,186 +156,437 @@ func (cursor *Cursor) Paginate(columns ...string) *Cursor {
 // Order set the pagination orders, and returns self.
 // The orders must be same order as columns that set to arguments of Paginate.
 func (cursor *Cursor) Order(orders ...string) *Cursor {
-	pbcOrders := make([]pbc.Order, len(orders))
-	nullsOrders := make([]pbc.NullsOrder, len(orders))
-	for i, rawOrder := range orders {
-		lowerRawOrder := strings.ToLower(rawOrder)
-		pbcOrders[i] = func() pbc.Order {
-			if strings.Contains(lowerRawOrder, string(pbc.DESC)) {
-				return pbc.DESC
-			}
-			return pbc.ASC
-		}()
-		nullsOrders[i] = func() pbc.NullsOrder {
-			if strings.Contains(lowerRawOrder, "first") {
-				if pbcOrders[i] == pbc.ASC {
-					return pbc.TreatsAsLowest
-				}
-				return pbc.TreatsAsHighest
-			} else if strings.Contains(lowerRawOrder, "last") {
-				if pbcOrders[i] == pbc.ASC {
-					return pbc.TreatsAsHighest
-				}
-				return pbc.TreatsAsLowest
-			}
-			return pbc.TreatsAsEngineDefault
-		}()
-	}
-
 	cursor.rawOrders = orders
-	cursor.orders = pbcOrders
-	cursor.nullsOrders = nullsOrders
+	cursor.orders, cursor.nullsOrders = parseOrders(orders)
+	return cursor
+}
+
+// Limits set the upper bounds of Limit, and returns self.
+// The bounds are checked by Validate and again before the query is executed.
+func (cursor *Cursor) Limits(limits Limits) *Cursor {
+	cursor.limits = limits
+	return cursor
+}
+
+// TieBreaker set the policy for the columns that are not unique, and returns self.
+// The default is TieBreakerIgnore.
+func (cursor *Cursor) TieBreaker(policy TieBreakerPolicy) *Cursor {
+	cursor.tieBreaker = policy
+	return cursor
+}
+
+// Counts set the configuration of counts, and returns self.
+// The counts are executed before the query, and you can get them by Summary.
+func (cursor *Cursor) Counts(counts Counts) *Cursor {
+	cursor.counts = counts
+	return cursor
+}
+
+// Summary returns a CursorSummary.
+// It is the result of the last query executed with Scope.
+func (cursor *Cursor) Summary() *CursorSummary {
+	return cursor.lastResult().Summary()
+}
 
+// SummaryWithURLs returns a CursorSummary that has the URLs to access from the base.
+// It is the result of the last query executed with Scope.
+func (cursor *Cursor) SummaryWithURLs(base *url.URL) *CursorSummary {
+	return cursor.lastResult().summaryWithURLs(base, cursor.getParamNames())
+}
+
+// PaginationSummary returns the same as Summary. See: Paginator
+func (cursor *Cursor) PaginationSummary() any {
+	return cursor.Summary()
+}
+
+// Links returns the links to the first, previous and next records.
+// It is the result of the last query executed with Scope.
+// The names of the parameters are the names of DetectPaginator, or DefaultParamNames.
+func (cursor *Cursor) Links(base *url.URL) *PagingLinks {
+	return cursor.lastResult().links(base, cursor.getParamNames())
+}
+
+// Fill set the end of the range that the records are read from, and returns self.
+// It is useful to fill a gap between Before and After from either end, and the records are returned in the order of the cursor.
+// See: CursorResult.GapClosed
+func (cursor *Cursor) Fill(direction FillDirection) *Cursor {
+	cursor.fill = direction
+	return cursor
+}
+
+// ItemCursors set whether to make the cursor of each record, and returns self.
+// The cursors are parallel to the records, and you can get them by GetItemCursors.
+func (cursor *Cursor) ItemCursors(enabled bool) *Cursor {
+	cursor.itemCursors = enabled
 	return cursor
 }
 
+// GetItemCursors returns the cursors of each record when ItemCursors is enabled.
+// It is the result of the last query executed with Scope.
+func (cursor *Cursor) GetItemCursors() []pbc.CursorString {
+	return cursor.lastResult().GetItemCursors()
+}
+
 // Scope returns a GORM scope.
+// The result of the query can be read from the Cursor, but it is overwritten by other queries executed with Scope.
+// Please use ScopeWithResult if you share the Cursor.
 func (cursor *Cursor) Scope() func(db *gorm.DB) *gorm.DB {
+	return cursor.scope(nil)
+}
+
+// ScopeWithResult returns a GORM scope that writes the result of the query to the result.
+func (cursor *Cursor) ScopeWithResult(result *CursorResult) func(db *gorm.DB) *gorm.DB {
+	if result == nil {
+		panic("result must not be nil")
+	}
+	return cursor.scope(result)
+}
+
+func (cursor *Cursor) scope(result *CursorResult) func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
-		cursor.baseOrder = pbc.ASC
-		if len(cursor.orders) > 0 {
-			cursor.baseOrder = cursor.orders[0]
+		query := &cursorQuery{cursor: cursor, result: result}
+		if result == nil {
+			query.result = &CursorResult{}
+			query.isLast = true
 		}
-
-		db = db.InstanceSet("pageboy:cursor", cursor)
+		db = db.InstanceSet("pageboy:cursor", query)
 		return db.Limit(cursor.Limit)
 	}
 }
 
-func (cursor *Cursor) comparisons(isBefore bool) []pbc.Comparison {
-	comparisons := make([]pbc.Comparison, len(cursor.columns))
-	ordersLength := len(cursor.orders)
+// countScope returns a scope that counts records in the range of the cursor.
+// Unlike Scope, it does not specify the order and the limit.
+func (cursor *Cursor) countScope() func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.InstanceSet("pageboy:cursor", &cursorQuery{cursor: cursor, result: &CursorResult{}, countOnly: true})
+	}
+}
 
-	isReverse := func(order pbc.Order) bool {
-		if cursor.baseOrder == order {
-			return false
-		}
+// clone returns a copy of the configuration.
+func (cursor *Cursor) clone() *Cursor {
+	return &Cursor{
+		Before:      cursor.Before,
+		After:       cursor.After,
+		Until:       cursor.Until,
+		Since:       cursor.Since,
+		Around:      cursor.Around,
+		Limit:       cursor.Limit,
+		Reverse:     cursor.Reverse,
+		rawOrders:   cursor.rawOrders,
+		orders:      cursor.orders,
+		nullsOrders: cursor.nullsOrders,
+		columns:     cursor.columns,
+		limits:      cursor.limits,
+		itemCursors: cursor.itemCursors,
+		tieBreaker:  cursor.tieBreaker,
+		fill:        cursor.fill,
+		counts:      cursor.counts,
+		paramNames:  cursor.paramNames,
+	}
+}
+
+// isForward returns true if the next records are after the current position.
+func (cursor *Cursor) isForward() bool {
+	return (cursor.baseOrder() == pbc.ASC) != cursor.Reverse
+}
+
+// readsForward returns true if the records are read from After to Before.
+func (cursor *Cursor) readsForward() bool {
+	switch cursor.fill {
+	case FillFromAfter:
 		return true
+	case FillFromBefore:
+		return false
 	}
+	return cursor.isForward()
+}
 
-	for i := 0; i < len(cursor.columns); i++ {
-		order := func() pbc.Order {
-			if i < ordersLength {
-				return cursor.orders[i]
-			}
-			return cursor.baseOrder
-		}()
+// next returns a copy of the cursor that is moved to the position.
+// The position is a value of After when readsForward is true, otherwise a value of Before.
+func (cursor *Cursor) next(position pbc.CursorString) *Cursor {
+	next := cursor.clone()
+	if next.readsForward() {
+		next.After, next.Since = position, ""
+	} else {
+		next.Before, next.Until = position, ""
+	}
+	return next
+}
 
-		if isBefore == isReverse(order) {
-			comparisons[i] = pbc.GreaterThan
-		} else {
-			comparisons[i] = pbc.LessThan
-		}
+func (cursor *Cursor) getParamNames() ParamNames {
+	if cursor.paramNames != nil {
+		return *cursor.paramNames
+	}
+	return DefaultParamNames
+}
+
+func (cursor *Cursor) lastResult() *CursorResult {
+	if result, ok := cursor.last.Load().(*CursorResult); ok {
+		return result
 	}
-	return comparisons
+	return &CursorResult{}
 }
 
-func getCursor(db *gorm.DB) (*Cursor, bool) {
+func (cursor *Cursor) baseOrder() pbc.Order {
+	if len(cursor.orders) > 0 {
+		return cursor.orders[0]
+	}
+	return pbc.ASC
+}
+
+func (cursor *Cursor) comparisons(isBefore bool) []pbc.Comparison {
+	return makeComparisons(len(cursor.columns), cursor.orders, cursor.baseOrder(), isBefore)
+}
+
+func getCursorQuery(db *gorm.DB) (*cursorQuery, bool) {
 	value, ok := db.InstanceGet("pageboy:cursor")
 	if !ok {
 		return nil, false
 	}
-	cursor, ok := value.(*Cursor)
+	query, ok := value.(*cursorQuery)
 	if !ok {
 		return nil, false
 	}
-	return cursor, true
+	return query, true
+}
+
+// inclusiveComparisons returns the comparisons that include the position that has the length values.
+func inclusiveComparisons(comparisons []pbc.Comparison, length int) []pbc.Comparison {
+	inclusive := append([]pbc.Comparison{}, comparisons...)
+	if length > 0 {
+		inclusive[length-1] = inclusive[length-1].Inclusive()
+	}
+	return inclusive
+}
+
+// isValidPosition returns true if the position may have the values of the columns.
+// The position made after appending the tie-breaker has the values of the primary keys in addition,
+// so the number of them is checked again in the query. See: cursorQuery.checkPosition
+func (cursor *Cursor) isValidPosition(position pbc.CursorString) bool {
+	if !position.Validate() {
+		return false
+	}
+	length := len(pbc.NewCursorSegments(position))
+	return length == len(cursor.columns) || (cursor.tieBreaker == TieBreakerAppend && length > len(cursor.columns))
+}
+
+// checkPosition returns an error when the values of the position cannot be decoded by the type.
+// The position has the values of the original columns, or the values of the columns after appending the tie-breaker.
+func (query *cursorQuery) checkPosition(field string, ty reflect.Type, position pbc.CursorString) error {
+	if position == "" {
+		return nil
+	}
+	if !position.Validate() {
+		return &ValidationError{Field: field, Message: "is invalid"}
+	}
+	segments := pbc.NewCursorSegments(position)
+	if len(segments) != len(query.cursor.columns) && len(segments) != len(query.keyset.columns) {
+		return &ValidationError{Field: field, Message: "is invalid"}
+	}
+	// NOTE: the values except integers (e.g. time) cannot be decoded without the model, and they are compared incorrectly.
+	if isUntypedModel(ty) && !isIntegerSegments(segments) {
+		return errUntypedCursor
+	}
+	return nil
+}
+
+// positionValues returns the values of the position.
+// The position made before appending the tie-breaker has fewer values, so it is compared by the original columns.
+func (query *cursorQuery) positionValues(ty reflect.Type, position pbc.CursorString) []interface{} {
+	segments := pbc.NewCursorSegments(position)
+	columns := query.keyset.columns
+	if len(segments) == len(query.cursor.columns) {
+		columns = query.cursor.columns
+	}
+	return segments.Interface(ty, columns...)
 }
 
 func cursorHandleBeforeQuery(db *gorm.DB) {
-	cursor, ok := getCursor(db)
+	query, ok := getCursorQuery(db)
 	if !ok {
 		return
 	}
-
-	dest := db.Statement.Dest
-	ty := reflect.TypeOf(dest)
-	for ty.Kind() == reflect.Ptr || ty.Kind() == reflect.Array || ty.Kind() == reflect.Slice {
-		ty = ty.Elem()
+	if query.cursor.Around != "" {
+		db.AddError(ErrAroundRequiresFindAround)
+		return
+	}
+	cursor, err := query.cursor.withTieBreaker(db)
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+	query.keyset = cursor
+
+	query.result.total, query.result.remaining = nil, nil
+	if !query.countOnly && cursor.counts.Total != CountNone {
+		total, err := countRecords(db, cursor.counts.Total, cursor.counts.Cap)
+		if err != nil {
+			db.AddError(err)
+			return
+		}
+		query.result.total = total
 	}
 
-	table := db.Statement.Table
-	columns := make([]string, len(cursor.columns))
-	for i, column := range cursor.columns {
-		buf := bytes.NewBuffer([]byte{})
-		db.Dialector.QuoteTo(buf, table+"."+column)
-		columns[i] = buf.String()
+	ty := getModelType(db)
+	columns := quoteColumns(db, cursor.columns)
+	for _, position := range []struct {
+		field string
+		value pbc.CursorString
+	}{{"Before", cursor.Before}, {"After", cursor.After}, {"Until", cursor.Until}, {"Since", cursor.Since}} {
+		if err := query.checkPosition(position.field, ty, position.value); err != nil {
+			db.AddError(err)
+			return
+		}
 	}
 
 	if cursor.Before != "" {
-		segments := pbc.NewCursorSegments(cursor.Before)
-		args := segments.Interface(ty, cursor.columns...)
+		args := query.positionValues(ty, cursor.Before)
 		db = pbc.MakeComparisonScope(columns, cursor.comparisons(true), cursor.nullsOrders, args)(db)
 	}
 
 	if cursor.After != "" {
-		segments := pbc.NewCursorSegments(cursor.After)
-		args := segments.Interface(ty, cursor.columns...)
+		args := query.positionValues(ty, cursor.After)
 		db = pbc.MakeComparisonScope(columns, cursor.comparisons(false), cursor.nullsOrders, args)(db)
 	}
 
-	if cursor.Reverse {
+	if cursor.Until != "" {
+		args := query.positionValues(ty, cursor.Until)
+		db = pbc.MakeComparisonScope(columns, inclusiveComparisons(cursor.comparisons(true), len(args)), cursor.nullsOrders, args)(db)
+	}
+
+	if cursor.Since != "" {
+		args := query.positionValues(ty, cursor.Since)
+		db = pbc.MakeComparisonScope(columns, inclusiveComparisons(cursor.comparisons(false), len(args)), cursor.nullsOrders, args)(db)
+	}
+
+	if query.countOnly {
+		return
+	}
+
+	if cursor.counts.Remaining != CountNone {
+		remaining, err := countRecords(db, cursor.counts.Remaining, cursor.counts.Cap)
+		if err != nil {
+			db.AddError(err)
+			return
+		}
+		query.result.remaining = remaining
+	}
+
+	query.isFlipped = cursor.readsForward() != cursor.isForward()
+	if cursor.Reverse != query.isFlipped {
 		db = db.Order(pbc.OrderClauseBuilder(columns...)(pbc.ReverseOrders(cursor.rawOrders)...))
 	} else {
 		db = db.Order(pbc.OrderClauseBuilder(columns...)(cursor.rawOrders...))
 	}
 
-	limit, ok := db.Statement.Clauses[new(clause.Limit).Name()]
-	if ok && limit.Expression.(clause.Limit).Limit != nil {
-		cursor.limit = *limit.Expression.(clause.Limit).Limit
-		db.Limit(cursor.limit + 1)
+	limit, ok := getLimitClause(db)
+	if ok && limit.Limit != nil {
+		query.limit = *limit.Limit
 	} else {
-		cursor.limit = -1
+		query.limit = -1
+	}
+
+	// NOTE: the limit may be overwritten after the scope.
+	if cursor.limits.MaxLimit > 0 {
+		requested := query.limit
+		if requested == -1 {
+			requested = math.MaxInt
+		}
+		limit, err := cursor.limits.enforce("Limit", requested, cursor.limits.MaxLimit)
+		if err != nil {
+			db.AddError(err)
+			return
+		}
+		query.limit = limit
+	}
+
+	if query.limit != -1 {
+		db.Limit(query.limit + 1)
 	}
 }
 
 func cursorHandleAfterQuery(db *gorm.DB) {
-	cursor, ok := getCursor(db)
-	if !ok {
+	query, ok := getCursorQuery(db)
+	if !ok || query.countOnly {
 		return
 	}
 
-	cursor.hasMore = false
-	if cursor.limit == -1 {
-		return
-	}
+	query.result.hasMore = false
 
 	results := db.Statement.ReflectValue
 	if !(results.Kind() == reflect.Array || results.Kind() == reflect.Slice) {
 		return
 	}
 
-	if cursor.limit+1 == results.Len() {
-		cursor.hasMore = true
+	if query.limit != -1 && query.limit+1 == results.Len() {
+		query.result.hasMore = true
 		results.Set(results.Slice(0, results.Len()-1))
 	}
+
+	// NOTE: the records read in the reverse order are returned in the order of the cursor.
+	if query.isFlipped && results.Kind() == reflect.Slice {
+		swap := reflect.Swapper(results.Interface())
+		for i, j := 0, results.Len()-1; i < j; i, j = i+1, j-1 {
+			swap(i, j)
+		}
+	}
 }
 
 func cursorHandleQuery(db *gorm.DB) {
-	cursor, ok := getCursor(db)
-	if !ok {
+	query, ok := getCursorQuery(db)
+	if !ok || query.countOnly {
 		return
 	}
-
-	cursor.nextBefore = ""
-	cursor.nextAfter = ""
+	cursor, result := query.cursor, query.result
+
+	result.nextBefore = ""
+	result.nextAfter = ""
+	result.itemCursors = nil
+	result.baseOrder = cursor.baseOrder()
+	result.reverse = cursor.Reverse
+	result.isFlipped = query.isFlipped
+	result.limit = query.limit
+	if cursor.readsForward() {
+		result.hasPrevious = cursor.After != "" || cursor.Since != ""
+	} else {
+		result.hasPrevious = cursor.Before != "" || cursor.Until != ""
+	}
+	result.gapClosed = false
+	if query.isLast {
+		defer cursor.last.Store(result)
+	}
+	if query.keyset != nil {
+		cursor = query.keyset
+	}
 
 	if db.Error != nil {
 		return
 	}
+	result.gapClosed = !result.hasMore &&
+		(cursor.Before != "" || cursor.Until != "") && (cursor.After != "" || cursor.Since != "")
 	results := db.Statement.ReflectValue
 	if !(results.Kind() == reflect.Array || results.Kind() == reflect.Slice) {
 		return
 	}
 
 	length := results.Len()
+	if length > 0 && isUntypedModel(getModelType(db)) && !isIntegerValues(getValuesFromColumns(results.Index(0), cursor.columns...)) {
+		db.AddError(errUntypedCursor)
+		return
+	}
+	if cursor.itemCursors {
+		result.itemCursors = make([]pbc.CursorString, length)
+		for i := 0; i < length; i++ {
+			result.itemCursors[i] = getCursorStringFromColumns(results.Index(i), cursor.columns...)
+		}
+	}
 	if length > 0 {
-		if (cursor.baseOrder == pbc.ASC) != cursor.Reverse {
-			cursor.nextAfter = getCursorStringFromColumns(results.Index(length-1), cursor.columns...)
-			cursor.nextBefore = getCursorStringFromColumns(results.Index(0), cursor.columns...)
+		if cursor.isForward() {
+			result.nextAfter = getCursorStringFromColumns(results.Index(length-1), cursor.columns...)
+			result.nextBefore = getCursorStringFromColumns(results.Index(0), cursor.columns...)
 		} else {
-			cursor.nextAfter = getCursorStringFromColumns(results.Index(0), cursor.columns...)
-			cursor.nextBefore = getCursorStringFromColumns(results.Index(length-1), cursor.columns...)
+			result.nextAfter = getCursorStringFromColumns(results.Index(0), cursor.columns...)
+			result.nextBefore = getCursorStringFromColumns(results.Index(length-1), cursor.columns...)
 		}
 	} else {
 		ty := results.Type().Elem()
@@ -295,41 +595,37 @@ func cursorHandleQuery(db *gorm.DB) {
 		}
 
 		if cursor.After != "" {
-			cursor.nextAfter = cursor.After
+			result.nextAfter = cursor.After
+		} else if cursor.Since != "" {
+			result.nextAfter = cursor.Since
 		} else {
-			cursor.nextAfter = getCursorStringFromColumns(reflect.New(ty), cursor.columns...)
+			result.nextAfter = getCursorStringFromColumns(reflect.New(ty), cursor.columns...)
 		}
 
 		if cursor.Before != "" {
-			cursor.nextBefore = cursor.Before
+			result.nextBefore = cursor.Before
+		} else if cursor.Until != "" {
+			result.nextBefore = cursor.Until
 		} else {
-			cursor.nextBefore = getCursorStringFromColumns(reflect.New(ty), cursor.columns...)
+			result.nextBefore = getCursorStringFromColumns(reflect.New(ty), cursor.columns...)
 		}
 	}
 }
 
+// CursorOf returns the cursor of the record, that is made by values of the columns.
+// It is the same as the values of Before and After that Cursor makes, so the record can be used as the position.
+// If the record is not a struct or the columns do not exist, it panics.
+//
+//	after := pageboy.CursorOf(user, "CreatedAt", "ID")
+func CursorOf(record any, columns ...string) pbc.CursorString {
+	return getCursorStringFromColumns(reflect.ValueOf(record), columns...)
+}
+
 func getCursorStringFromColumns(value reflect.Value, columns ...string) pbc.CursorString {
-	value = reflect.Indirect(value)
-	if !(value.Kind() == reflect.Struct) {
-		panic("Find result is not a struct or an array of struct.")
-	}
 	if len(columns) == 0 {
 		return ""
 	}
-
-	args := make([]interface{}, len(columns))
-	for i, column := range columns {
-		argValue := value.FieldByName(column)
-		if !argValue.IsValid() {
-			panic("`" + column + "` field is not exist in " + value.Type().Name() + ".")
-		} else if argValue.CanInterface() {
-			args[i] = argValue.Interface()
-		} else {
-			args[i] = nil
-		}
-	}
-
-	return pbc.FormatCursorString(args...)
+	return pbc.FormatCursorString(getValuesFromColumns(value, columns...)...)
 }
 
 func registerCursorCallbacks(db *gorm.DB) {
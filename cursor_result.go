@@ -0,0 +1,191 @@
+package pageboy
+
+import (
+	"net/url"
+
+	pbc "github.com/soranoba/pageboy/v4/core"
+)
+
+// CursorResult is the result of a query paginated by Cursor.
+// See: Cursor.ScopeWithResult
+type CursorResult struct {
+	nextBefore pbc.CursorString
+	nextAfter  pbc.CursorString
+	baseOrder  pbc.Order
+	reverse    bool
+	hasMore    bool
+	// limit is the limit of the query. It is -1 when the query is unlimited.
+	limit int
+	// isFlipped is true when the records are read in the reverse order by the fill direction.
+	isFlipped bool
+	// hasPrevious is true when the query has the position that it starts from.
+	hasPrevious bool
+	// See: Cursor.ItemCursors
+	itemCursors []pbc.CursorString
+	gapClosed   bool
+	// See: Cursor.Counts
+	total     *CountSummary
+	remaining *CountSummary
+}
+
+// CursorSummary is summary of the query paginated by Cursor.
+// The URLs are set only by SummaryWithURLs, and the counts are nil when they are not counted. See: Cursor.Counts
+type CursorSummary struct {
+	Limit      int              `json:"limit"`
+	NextBefore pbc.CursorString `json:"next_before"`
+	NextAfter  pbc.CursorString `json:"next_after"`
+	HasMore    bool             `json:"has_more"`
+	Reverse    bool             `json:"reverse"`
+	PrevURL    string           `json:"prev_url,omitempty"`
+	NextURL    string           `json:"next_url,omitempty"`
+	Total      *CountSummary    `json:"total,omitempty"`
+	Remaining  *CountSummary    `json:"remaining,omitempty"`
+}
+
+// GetNextAfter returns a value of query to access if it exists some records after the current position.
+func (result *CursorResult) GetNextAfter() pbc.CursorString {
+	return result.nextAfter
+}
+
+// GetNextBefore returns a value of query to access if it exists some records before the current position.
+func (result *CursorResult) GetNextBefore() pbc.CursorString {
+	return result.nextBefore
+}
+
+// HasMore returns true if it exists some records at target of next.
+func (result *CursorResult) HasMore() bool {
+	return result.hasMore
+}
+
+// Summary returns a CursorSummary.
+func (result *CursorResult) Summary() *CursorSummary {
+	limit := result.limit
+	if limit < 0 {
+		limit = 0
+	}
+	return &CursorSummary{
+		Limit:      limit,
+		NextBefore: result.nextBefore,
+		NextAfter:  result.nextAfter,
+		HasMore:    result.hasMore,
+		Reverse:    result.reverse,
+		Total:      result.total,
+		Remaining:  result.remaining,
+	}
+}
+
+// SummaryWithURLs returns a CursorSummary that has the URLs to access from the base.
+// NextURL is the same as BuildNextPagingUrls, and PrevURL reads the records before the current records in the reverse order.
+// PrevURL is set only when the query has the position that it starts from.
+func (result *CursorResult) SummaryWithURLs(base *url.URL) *CursorSummary {
+	return result.summaryWithURLs(base, DefaultParamNames)
+}
+
+func (result *CursorResult) summaryWithURLs(base *url.URL, names ParamNames) *CursorSummary {
+	summary := result.Summary()
+	if base == nil {
+		return summary
+	}
+	summary.NextURL = result.nextURL(base, names)
+	summary.PrevURL = result.prevURL(base, names)
+	return summary
+}
+
+// Links returns the links to the first, previous and next records.
+// Prev and Next are the same as SummaryWithURLs, and Last is always omitted.
+func (result *CursorResult) Links(base *url.URL) *PagingLinks {
+	return result.links(base, DefaultParamNames)
+}
+
+func (result *CursorResult) links(base *url.URL, names ParamNames) *PagingLinks {
+	links := &PagingLinks{}
+	if base == nil {
+		return links
+	}
+
+	links.First = withQuery(base, func(query url.Values) {
+		for _, name := range []string{names.Before, names.After, names.Until, names.Since, names.Around} {
+			query.Del(name)
+		}
+	})
+	links.Prev = result.prevURL(base, names)
+	links.Next = result.nextURL(base, names)
+	return links
+}
+
+// nextURL returns the URL to access the next records, or an empty string when they do not exist.
+func (result *CursorResult) nextURL(base *url.URL, names ParamNames) string {
+	name, position, inclusive := names.After, result.nextAfter, names.Since
+	if !result.isForward() {
+		name, position, inclusive = names.Before, result.nextBefore, names.Until
+	}
+	if !result.hasMore || name == "" {
+		return ""
+	}
+	return withQuery(base, func(query url.Values) {
+		query.Del(inclusive)
+		query.Set(name, string(position))
+	})
+}
+
+// prevURL returns the URL to access the records before the current records in the reverse order.
+// It is an empty string when the query does not have the position that it starts from.
+func (result *CursorResult) prevURL(base *url.URL, names ParamNames) string {
+	name, position, opposites := names.Before, result.nextBefore, []string{names.After, names.Since}
+	if !result.isForward() {
+		name, position, opposites = names.After, result.nextAfter, []string{names.Before, names.Until}
+	}
+	if !result.hasPrevious || name == "" || names.Reverse == "" {
+		return ""
+	}
+	return withQuery(base, func(query url.Values) {
+		for _, opposite := range opposites {
+			query.Del(opposite)
+		}
+		query.Set(name, string(position))
+		if result.reverse {
+			query.Del(names.Reverse)
+		} else {
+			query.Set(names.Reverse, "true")
+		}
+	})
+}
+
+// GapClosed returns true if all records between Before and After are returned.
+// It is always false when either of them is not specified.
+func (result *CursorResult) GapClosed() bool {
+	return result.gapClosed
+}
+
+// GetItemCursors returns the cursors of each record when Cursor.ItemCursors is enabled.
+// They are parallel to the records.
+func (result *CursorResult) GetItemCursors() []pbc.CursorString {
+	return result.itemCursors
+}
+
+// BuildNextPagingUrls returns URLs for the user to access from the next cursor position.
+//
+// You can use GetNextBefore and GetNextAfter if you want to customize the behavior.
+func (result *CursorResult) BuildNextPagingUrls(base *url.URL) *CursorPagingUrls {
+	pagingUrls := &CursorPagingUrls{}
+
+	if base == nil {
+		return pagingUrls
+	}
+
+	pagingUrls.Next = result.nextURL(base, DefaultParamNames)
+	return pagingUrls
+}
+
+// isForward returns true if the next records are after the current position.
+func (result *CursorResult) isForward() bool {
+	return (result.baseOrder == pbc.ASC) != result.reverse != result.isFlipped
+}
+
+// nextPosition returns the position to access the next records.
+func (result *CursorResult) nextPosition() pbc.CursorString {
+	if result.isForward() {
+		return result.nextAfter
+	}
+	return result.nextBefore
+}
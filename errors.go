@@ -1,6 +1,16 @@
 package pageboy
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrPageOutOfRange is an error returned when the page is greater than the total page.
+// See: OutOfRangeError
+var ErrPageOutOfRange = errors.New("page is out of range")
+
+// errUntypedCursor is an error returned when the cursor has the values except integers, and the model is not specified.
+var errUntypedCursor = fmt.Errorf("%w to decode the cursor that has the values except integers", ErrModelRequired)
 
 // ValidationError is a validation error.
 type ValidationError struct {
@@ -0,0 +1,97 @@
+package pageboy
+
+import (
+	"context"
+
+	pbc "github.com/soranoba/pageboy/v4/core"
+	"gorm.io/gorm"
+)
+
+// Iterator is an iterator that reads all records by Cursor in batches.
+// It can follow any columns that can be paginated by Cursor, unlike FindInBatches of GORM.
+//
+//	it := pageboy.NewIterator[*User](db, cursor)
+//	for it.Next(ctx) {
+//		users := it.Batch()
+//	}
+//	if err := it.Err(); err != nil {
+//		return err
+//	}
+type Iterator[T any] struct {
+	db       *gorm.DB
+	cursor   *Cursor
+	batch    []T
+	position pbc.CursorString
+	isDone   bool
+	err      error
+}
+
+// NewIterator returns an Iterator that starts at the position of the cursor.
+// The Limit of the cursor is used as the batch size.
+func NewIterator[T any](db *gorm.DB, cursor *Cursor) *Iterator[T] {
+	it := &Iterator[T]{db: db, cursor: cursor.clone()}
+	if err := it.cursor.Validate(); err != nil {
+		it.err = err
+	}
+	return it
+}
+
+// Next reads the next batch, and returns true if it exists.
+// When it returns false, you should check Err.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	if it.isDone || it.err != nil {
+		return false
+	}
+	if err := ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+
+	batch := make([]T, 0, it.cursor.Limit)
+	result := &CursorResult{}
+	if err := it.db.WithContext(ctx).Scopes(it.cursor.ScopeWithResult(result)).Find(&batch).Error; err != nil {
+		it.err = err
+		return false
+	}
+	if len(batch) == 0 {
+		it.isDone = true
+		return false
+	}
+
+	it.batch = batch
+	it.position = result.nextPosition()
+	if result.HasMore() {
+		it.cursor = it.cursor.next(it.position)
+	} else {
+		it.isDone = true
+	}
+	return true
+}
+
+// Batch returns the records read by Next.
+func (it *Iterator[T]) Batch() []T {
+	return it.batch
+}
+
+// Position returns the position after the last record of Batch.
+// The iteration can be resumed from there by the cursor that is set it to After (or Before when the order is descending).
+func (it *Iterator[T]) Position() pbc.CursorString {
+	return it.position
+}
+
+// Err returns the error that occurred during the iteration.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Each calls fn with each batch of the records read by Iterator.
+// If fn returns an error, it stops the iteration and returns the error.
+func Each[T any](ctx context.Context, db *gorm.DB, cursor *Cursor, fn func(batch []T) error) error {
+	it := NewIterator[T](db, cursor)
+	for it.Next(ctx) {
+		if err := fn(it.Batch()); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
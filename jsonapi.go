@@ -0,0 +1,56 @@
+package pageboy
+
+import (
+	"net/url"
+)
+
+// JSONAPILinks is the pagination links of JSON:API.
+// See: https://jsonapi.org/format/#fetching-pagination
+type JSONAPILinks = PagingLinks
+
+// BindJSONAPICursor sets `page[size]`, `page[after]`, `page[before]` and `page[reverse]` of the query to the cursor.
+// The parameters that the query does not have are not changed.
+// See: JSONAPIParamNames
+func BindJSONAPICursor(query url.Values, cursor *Cursor) error {
+	return JSONAPIParamNames.bindCursor(query, cursor)
+}
+
+// BindJSONAPIPager sets `page[number]` and `page[size]` of the query to the pager.
+// The parameters that the query does not have are not changed.
+// See: JSONAPIParamNames
+func BindJSONAPIPager(query url.Values, pager *Pager) error {
+	return JSONAPIParamNames.bindPager(query, pager)
+}
+
+// BuildJSONAPILinks returns the pagination links of JSON:API.
+// It is the result of the last query executed with Scope.
+func (cursor *Cursor) BuildJSONAPILinks(base *url.URL) *JSONAPILinks {
+	return cursor.lastResult().BuildJSONAPILinks(base)
+}
+
+// BuildJSONAPILinks returns the pagination links of JSON:API.
+// They are the same as Links with JSONAPIParamNames, so Prev reads the records before the current records with `page[reverse]`.
+func (result *CursorResult) BuildJSONAPILinks(base *url.URL) *JSONAPILinks {
+	return result.links(base, JSONAPIParamNames)
+}
+
+// BuildJSONAPILinks returns the pagination links of JSON:API.
+// It is the result of the last query executed with Scope.
+func (pager *Pager) BuildJSONAPILinks(base *url.URL) *JSONAPILinks {
+	return pager.lastResult().BuildJSONAPILinks(base)
+}
+
+// BuildJSONAPILinks returns the pagination links of JSON:API.
+// Prev and Next are omitted when the page does not exist.
+func (result *PagerResult) BuildJSONAPILinks(base *url.URL) *JSONAPILinks {
+	return result.links(base, JSONAPIParamNames)
+}
+
+// withQuery returns the URL that the query of the base is modified.
+func withQuery(base *url.URL, modify func(query url.Values)) string {
+	u := *base
+	query := u.Query()
+	modify(query)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
@@ -0,0 +1,207 @@
+package pageboy
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"time"
+
+	pbc "github.com/soranoba/pageboy/v4/core"
+	"gorm.io/gorm"
+)
+
+// parseOrders returns the orders and the nulls orders parsed from the raw orders such as "DESC NULLS LAST".
+func parseOrders(orders []string) ([]pbc.Order, []pbc.NullsOrder) {
+	pbcOrders := make([]pbc.Order, len(orders))
+	nullsOrders := make([]pbc.NullsOrder, len(orders))
+	for i, rawOrder := range orders {
+		lowerRawOrder := strings.ToLower(rawOrder)
+		pbcOrders[i] = func() pbc.Order {
+			if strings.Contains(lowerRawOrder, string(pbc.DESC)) {
+				return pbc.DESC
+			}
+			return pbc.ASC
+		}()
+		nullsOrders[i] = func() pbc.NullsOrder {
+			if strings.Contains(lowerRawOrder, "first") {
+				if pbcOrders[i] == pbc.ASC {
+					return pbc.TreatsAsLowest
+				}
+				return pbc.TreatsAsHighest
+			} else if strings.Contains(lowerRawOrder, "last") {
+				if pbcOrders[i] == pbc.ASC {
+					return pbc.TreatsAsHighest
+				}
+				return pbc.TreatsAsLowest
+			}
+			return pbc.TreatsAsEngineDefault
+		}()
+	}
+	return pbcOrders, nullsOrders
+}
+
+// makeComparisons returns the comparisons of each column to get records before (or after) the position.
+// Columns that have no order are treated as the baseOrder.
+func makeComparisons(length int, orders []pbc.Order, baseOrder pbc.Order, isBefore bool) []pbc.Comparison {
+	comparisons := make([]pbc.Comparison, length)
+	ordersLength := len(orders)
+
+	isReverse := func(order pbc.Order) bool {
+		if baseOrder == order {
+			return false
+		}
+		return true
+	}
+
+	for i := 0; i < length; i++ {
+		order := func() pbc.Order {
+			if i < ordersLength {
+				return orders[i]
+			}
+			return baseOrder
+		}()
+
+		if isBefore == isReverse(order) {
+			comparisons[i] = pbc.GreaterThan
+		} else {
+			comparisons[i] = pbc.LessThan
+		}
+	}
+	return comparisons
+}
+
+// quoteColumns returns the columns quoted with the table name of the statement.
+func quoteColumns(db *gorm.DB, columns []string) []string {
+	table := db.Statement.Table
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		buf := bytes.NewBuffer([]byte{})
+		db.Dialector.QuoteTo(buf, table+"."+column)
+		quoted[i] = buf.String()
+	}
+	return quoted
+}
+
+// getModelType returns the type of the model that is used to decode values of cursors.
+// It is the type of Statement.Model, because Dest is not a model when it is Count, Pluck or Scan into DTOs.
+// When the model is not a struct (e.g. db.Table("users").Find(&maps)), it returns the type of the records of Dest.
+func getModelType(db *gorm.DB) reflect.Type {
+	if ty := indirectType(reflect.TypeOf(db.Statement.Model)); ty != nil && ty.Kind() == reflect.Struct {
+		return ty
+	}
+	if db.Statement.Schema != nil {
+		return db.Statement.Schema.ModelType
+	}
+	return indirectType(reflect.TypeOf(db.Statement.Dest))
+}
+
+// isUntypedModel returns true if the types of the columns are unknown, such as maps without the model.
+// Then the values of cursors are decoded as integers.
+func isUntypedModel(ty reflect.Type) bool {
+	return ty.Kind() == reflect.Map || ty.Kind() == reflect.Interface
+}
+
+// isIntegerSegments returns true if all segments can be decoded as integers.
+func isIntegerSegments(segments pbc.CursorSegments) bool {
+	for _, segment := range segments {
+		if !segment.IsInteger() {
+			return false
+		}
+	}
+	return true
+}
+
+// isIntegerValues returns true if all values are encoded as integers, that is, the values except time.
+func isIntegerValues(values []interface{}) bool {
+	for _, value := range values {
+		v := reflect.ValueOf(value)
+		for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+			v = v.Elem()
+		}
+		switch v.Kind() {
+		case reflect.Invalid, reflect.Bool,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// indirectType returns the type of the elements of pointers, arrays and slices.
+func indirectType(ty reflect.Type) reflect.Type {
+	for ty != nil && (ty.Kind() == reflect.Ptr || ty.Kind() == reflect.Array || ty.Kind() == reflect.Slice) {
+		ty = ty.Elem()
+	}
+	return ty
+}
+
+// getValuesFromColumns returns the values of the columns of the record.
+// The record is a struct, a map of the column names (e.g. []map[string]interface{}), or a value of the column itself (e.g. Pluck).
+func getValuesFromColumns(value reflect.Value, columns ...string) []interface{} {
+	value = reflect.Indirect(value)
+	if value.Kind() == reflect.Interface {
+		value = reflect.Indirect(value.Elem())
+	}
+
+	switch {
+	case value.Kind() == reflect.Map:
+		return getValuesFromMap(value, columns...)
+	case value.Kind() == reflect.Struct && value.Type() != reflect.TypeOf(time.Time{}):
+	case value.IsValid():
+		if len(columns) != 1 {
+			panic("Find result is a scalar, but the number of columns is not one.")
+		}
+		return []interface{}{value.Interface()}
+	default:
+		panic("Find result is not a struct, a map or a scalar.")
+	}
+
+	args := make([]interface{}, len(columns))
+	for i, column := range columns {
+		argValue := value.FieldByName(column)
+		if !argValue.IsValid() {
+			panic("`" + column + "` field is not exist in " + value.Type().Name() + ".")
+		} else if argValue.CanInterface() {
+			args[i] = argValue.Interface()
+		} else {
+			args[i] = nil
+		}
+	}
+	return args
+}
+
+// getValuesFromMap returns the values of the columns of the map that has the values by the column names.
+// The column is looked up by the field name (e.g. CreatedAt), and the column name in the database (e.g. created_at).
+func getValuesFromMap(value reflect.Value, columns ...string) []interface{} {
+	args := make([]interface{}, len(columns))
+	if value.IsNil() || value.Len() == 0 {
+		return args
+	}
+
+	for i, column := range columns {
+		argValue := value.MapIndex(reflect.ValueOf(column))
+		if !argValue.IsValid() {
+			// NOTE: it is not used the naming strategy, because the keys are the names in the query result such as aliases.
+			normalized := normalizeColumnName(column)
+			iter := value.MapRange()
+			for iter.Next() {
+				if key, ok := iter.Key().Interface().(string); ok && normalizeColumnName(key) == normalized {
+					argValue = iter.Value()
+					break
+				}
+			}
+		}
+		if !argValue.IsValid() {
+			panic("`" + column + "` column is not exist in the map.")
+		}
+		args[i] = argValue.Interface()
+	}
+	return args
+}
+
+// normalizeColumnName returns the name to compare the field name with the column name. (e.g. CreatedAt and created_at)
+func normalizeColumnName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
@@ -0,0 +1,53 @@
+package pageboy
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LimitPolicy is how to handle values that exceed the Limits.
+type LimitPolicy int
+
+const (
+	// RejectOverLimit returns a ValidationError when the value exceeds the limit.
+	RejectOverLimit LimitPolicy = iota
+	// ClampOverLimit replaces the value that exceeds the limit with the limit.
+	ClampOverLimit
+)
+
+// Limits is the upper bounds of values accepted by Cursor and Pager.
+// Zero means unlimited.
+type Limits struct {
+	// MaxLimit is the upper bound of Cursor.Limit.
+	MaxLimit int
+	// MaxPerPage is the upper bound of Pager.PerPage.
+	MaxPerPage int
+	// MaxPage is the upper bound of Pager.Page.
+	MaxPage int
+	// MaxOffset is the upper bound of the offset that calculated from Pager.Page and Pager.PerPage.
+	MaxOffset int
+
+	Policy LimitPolicy
+}
+
+// enforce returns the value that is applied the max, or returns an error when the Policy is RejectOverLimit.
+func (limits Limits) enforce(field string, value int, max int) (int, error) {
+	if max <= 0 || value <= max {
+		return value, nil
+	}
+	if limits.Policy == ClampOverLimit {
+		return max, nil
+	}
+	return value, &ValidationError{Field: field, Message: fmt.Sprintf("must be less than or equal to %d", max)}
+}
+
+func getLimitClause(db *gorm.DB) (clause.Limit, bool) {
+	c, ok := db.Statement.Clauses[new(clause.Limit).Name()]
+	if !ok {
+		return clause.Limit{}, false
+	}
+	limit, ok := c.Expression.(clause.Limit)
+	return limit, ok
+}
@@ -0,0 +1,49 @@
+package pageboy
+
+import (
+	"gorm.io/gorm"
+)
+
+// CursorPage is the records paginated by Cursor and the pagination information.
+type CursorPage[T any] struct {
+	Items []T `json:"items"`
+	CursorSummary
+}
+
+// PagerPage is the records paginated by Pager and the pagination information.
+type PagerPage[T any] struct {
+	Items []T `json:"items"`
+	PagerSummary
+}
+
+// FindCursorPage finds the records paginated by the cursor, and returns them with the pagination information.
+// The result of the query is not written to the cursor.
+//
+//	page, err := pageboy.FindCursorPage[*User](db.Where("age > ?", 20), cursor)
+func FindCursorPage[T any](db *gorm.DB, cursor *Cursor) (*CursorPage[T], error) {
+	items := make([]T, 0)
+	result := &CursorResult{}
+	if err := db.Scopes(cursor.ScopeWithResult(result)).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return &CursorPage[T]{
+		Items:         items,
+		CursorSummary: *result.Summary(),
+	}, nil
+}
+
+// FindPagerPage finds the records paginated by the pager, and returns them with the pagination information.
+// The result of the query is not written to the pager.
+//
+//	page, err := pageboy.FindPagerPage[*User](db.Order("id ASC"), pager)
+func FindPagerPage[T any](db *gorm.DB, pager *Pager) (*PagerPage[T], error) {
+	items := make([]T, 0)
+	result := &PagerResult{}
+	if err := db.Scopes(pager.ScopeWithResult(result)).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return &PagerPage[T]{
+		Items:        items,
+		PagerSummary: *result.Summary(),
+	}, nil
+}
@@ -0,0 +1,129 @@
+package pageboy
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+
+	pbc "github.com/soranoba/pageboy/v4/core"
+	"gorm.io/gorm"
+)
+
+// PageTokenRequest is the pagination fields of a request of Google AIP-158.
+// See: https://google.aip.dev/158
+type PageTokenRequest struct {
+	// PageSize is the maximum number of records. When it is 0, the Limit of the cursor is used.
+	PageSize int32
+	// PageToken is the NextPageToken of the previous response.
+	PageToken string
+	// Params are the other parameters of the request (e.g. filter, order_by) that MUST NOT be changed between the pages.
+	// It is marshaled as JSON.
+	Params interface{}
+}
+
+// TokenPage is the records paginated by PageTokenRequest.
+// NextPageToken is empty when it is the end.
+type TokenPage[T any] struct {
+	Items         []T
+	NextPageToken string
+}
+
+// ErrInvalidPageToken is an error returned when the page token is broken or forged.
+var ErrInvalidPageToken = &ValidationError{Field: "PageToken", Message: "is invalid"}
+
+type pageToken struct {
+	Position pbc.CursorString `json:"p"`
+	Hash     string           `json:"h"`
+}
+
+// FindTokenPage finds the records paginated by the cursor with the fields of Google AIP-158.
+// The max page size is the MaxLimit of the cursor, and it should use ClampOverLimit as the policy as recommended by AIP-158.
+//
+// It returns a ValidationError when the request is invalid, or the page token does not match the request.
+// Please convert it into InvalidArgument.
+//
+//	cursor := pageboy.NewCursor().Paginate("CreatedAt", "ID").Order("DESC", "DESC").Limits(pageboy.Limits{MaxLimit: 100, Policy: pageboy.ClampOverLimit})
+//	page, err := pageboy.FindTokenPage[*User](db, cursor, pageboy.PageTokenRequest{PageSize: req.PageSize, PageToken: req.PageToken, Params: req.Filter})
+func FindTokenPage[T any](db *gorm.DB, cursor *Cursor, req PageTokenRequest) (*TokenPage[T], error) {
+	if req.PageSize < 0 {
+		return nil, &ValidationError{Field: "PageSize", Message: "must be greater than or equal to 0"}
+	}
+
+	c := cursor.clone()
+	if req.PageSize > 0 {
+		c.Limit = int(req.PageSize)
+	}
+
+	hash, err := c.pageTokenHash(req.Params)
+	if err != nil {
+		return nil, err
+	}
+	if req.PageToken != "" {
+		position, err := c.decodePageToken(req.PageToken, hash)
+		if err != nil {
+			return nil, err
+		}
+		c = c.next(position)
+	}
+
+	if err := c.Validate(); err != nil {
+		var validationErr *ValidationError
+		if errors.As(err, &validationErr) && validationErr.Field == "Limit" {
+			return nil, &ValidationError{Field: "PageSize", Message: validationErr.Message}
+		}
+		return nil, err
+	}
+
+	items := make([]T, 0, c.Limit)
+	result := &CursorResult{}
+	if err := db.Scopes(c.ScopeWithResult(result)).Find(&items).Error; err != nil {
+		// NOTE: the number of the values of the position is checked in the query, because it depends on the tie-breaker.
+		var validationErr *ValidationError
+		if req.PageToken != "" && errors.As(err, &validationErr) && (validationErr.Field == "Before" || validationErr.Field == "After") {
+			return nil, ErrInvalidPageToken
+		}
+		return nil, err
+	}
+
+	page := &TokenPage[T]{Items: items}
+	if result.HasMore() {
+		page.NextPageToken = encodePageToken(result.nextPosition(), hash)
+	}
+	return page, nil
+}
+
+// pageTokenHash returns a hash of the parameters and the configuration of the cursor.
+func (cursor *Cursor) pageTokenHash(params interface{}) (string, error) {
+	b, err := json.Marshal([]interface{}{params, cursor.columns, cursor.rawOrders, cursor.Reverse})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:16]), nil
+}
+
+func encodePageToken(position pbc.CursorString, hash string) string {
+	b, _ := json.Marshal(&pageToken{Position: position, Hash: hash})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func (cursor *Cursor) decodePageToken(token string, hash string) (pbc.CursorString, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return "", ErrInvalidPageToken
+	}
+	var t pageToken
+	if err := json.Unmarshal(b, &t); err != nil || t.Position == "" {
+		return "", ErrInvalidPageToken
+	}
+	if t.Hash != hash {
+		return "", &ValidationError{Field: "PageToken", Message: "does not match the request"}
+	}
+	// NOTE: the hash is not a signature, so the position may be forged.
+	if !cursor.isValidPosition(t.Position) {
+		return "", ErrInvalidPageToken
+	}
+	return t.Position, nil
+}
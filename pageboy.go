@@ -14,4 +14,5 @@ import "gorm.io/gorm"
 func RegisterCallbacks(db *gorm.DB) {
 	registerCursorCallbacks(db)
 	registerPagerCallbacks(db)
+	registerScanCallbacks(db)
 }
@@ -1,26 +1,79 @@
 package pageboy
 
 import (
+	"context"
 	"math"
+	"net/url"
+	"reflect"
+	"sync/atomic"
 
+	pbc "github.com/soranoba/pageboy/v4/core"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
 // Pager is a builder that build a GORM scope that specifies a range of records.
+//
+// When the pagination target columns are set by Paginate, the Pager returns a page anchor in the summary.
+// If the anchor is sent with the next page number, the Pager uses it instead of OFFSET.
+//
+// The results of queries are not written to the Pager when you use ScopeWithResult,
+// so the Pager can be shared by multiple goroutines after the configuration.
 type Pager struct {
-	Page    int `json:"page"     query:"page"`
-	PerPage int `json:"per_page" query:"per_page"`
+	Page    int              `json:"page"     query:"page"`
+	PerPage int              `json:"per_page" query:"per_page"`
+	Anchor  pbc.CursorString `json:"anchor"   query:"anchor"`
 
-	totalCount int64
+	// See: pager.Order
+	rawOrders   []string
+	orders      []pbc.Order
+	nullsOrders []pbc.NullsOrder
+	// See: pager.Paginate
+	columns []string
+	// See: pager.Limits
+	limits Limits
+	// See: pager.OutOfRange
+	outOfRange OutOfRangePolicy
+
+	// See: pager.ConcurrentCount
+	concurrentCount bool
+	// See: pager.CountDistinct
+	countDistinct bool
+	// The names of the parameters that the pager is bound by. See: ParamNames.DetectPaginator
+	paramNames *ParamNames
+
+	// The result of the last query executed with Scope. (*PagerResult)
+	last atomic.Value
+}
+
+// pagerQuery is the state of a query paginated by Pager.
+type pagerQuery struct {
+	pager     *Pager
+	result    *PagerResult
+	waitCount func(cancel bool) error
+	// isLast is true when the result is the last result of the pager.
+	isLast bool
 }
 
+// OutOfRangePolicy is how to handle the page that is greater than the total page.
+type OutOfRangePolicy int
+
+const (
+	// OutOfRangeEmpty returns no records.
+	OutOfRangeEmpty OutOfRangePolicy = iota
+	// OutOfRangeLastPage returns the records of the last page, and the page of PagerSummary is corrected.
+	OutOfRangeLastPage
+	// OutOfRangeError returns ErrPageOutOfRange.
+	OutOfRangeError
+)
+
 // PagerSummary is summary of the query.
 type PagerSummary struct {
-	Page       int   `json:"page"        query:"page"`
-	PerPage    int   `json:"per_page"    query:"per_page"`
-	TotalCount int64 `json:"total_count" query:"total_count"`
-	TotalPage  int   `json:"total_page"  query:"total_page"`
+	Page       int              `json:"page"                  query:"page"`
+	PerPage    int              `json:"per_page"              query:"per_page"`
+	TotalCount int64            `json:"total_count"           query:"total_count"`
+	TotalPage  int              `json:"total_page"            query:"total_page"`
+	NextAnchor pbc.CursorString `json:"next_anchor,omitempty" query:"next_anchor"`
 }
 
 // NewPager returns a default Pager.
@@ -32,49 +85,325 @@ func NewPager() *Pager {
 }
 
 // Summary returns a PagerSummary.
+// It is the result of the last query executed with Scope.
 func (pager *Pager) Summary() *PagerSummary {
-	return &PagerSummary{
-		Page:       pager.Page,
-		PerPage:    pager.PerPage,
-		TotalCount: pager.totalCount,
-		TotalPage:  int(math.Ceil(float64(pager.totalCount) / float64(pager.PerPage))),
-	}
+	return pager.lastResult().Summary()
+}
+
+// Links returns the links to the first, previous, next and last pages.
+// It is the result of the last query executed with Scope.
+// The names of the parameters are the names of DetectPaginator, or DefaultParamNames.
+func (pager *Pager) Links(base *url.URL) *PagingLinks {
+	return pager.lastResult().links(base, pager.getParamNames())
+}
+
+// PaginationSummary returns the same as Summary. See: Paginator
+func (pager *Pager) PaginationSummary() any {
+	return pager.Summary()
 }
 
 // Validate returns true when the values of Pager is valid. Otherwise, it returns false.
 // If you execute Paginate with an invalid values, it panic may occur.
 func (pager *Pager) Validate() error {
-	if pager.PerPage == 0 {
+	if pager.PerPage < 1 {
 		return &ValidationError{Field: "PerPage", Message: "must be greater than 0"}
 	}
-	if pager.Page == 0 {
+	if pager.Page < 1 {
 		return &ValidationError{Field: "Page", Message: "must be greater than 0"}
 	}
-	return nil
+	if pager.Anchor != "" && !pager.Anchor.Validate() {
+		return &ValidationError{Field: "Anchor", Message: "is invalid"}
+	}
+	// NOTE: the clamped values are not written back, because the pager may be shared. They are applied to each query.
+	_, _, err := pager.enforcedPage()
+	return err
+}
+
+// enforcedPage returns the page and the per page that are applied the limits.
+func (pager *Pager) enforcedPage() (int, int, error) {
+	perPage, err := pager.limits.enforce("PerPage", pager.PerPage, pager.limits.MaxPerPage)
+	if err != nil {
+		return pager.Page, pager.PerPage, err
+	}
+	page, err := pager.limits.enforce("Page", pager.Page, pager.limits.MaxPage)
+	if err != nil {
+		return pager.Page, pager.PerPage, err
+	}
+	if pager.limits.MaxOffset > 0 && perPage > 0 {
+		page, err = pager.limits.enforce("Page", page, pager.limits.MaxOffset/perPage+1)
+		if err != nil {
+			return pager.Page, pager.PerPage, err
+		}
+	}
+	return page, perPage, nil
+}
+
+// Paginate set the columns used for page anchors, and returns self.
+// If the columns are set, the records are sorted by them.
+func (pager *Pager) Paginate(columns ...string) *Pager {
+	pager.columns = columns
+	return pager
+}
+
+// Order set the orders of columns used for page anchors, and returns self.
+// The orders must be same order as columns that set to arguments of Paginate.
+func (pager *Pager) Order(orders ...string) *Pager {
+	pager.rawOrders = orders
+	pager.orders, pager.nullsOrders = parseOrders(orders)
+	return pager
+}
+
+// Limits set the upper bounds of Page and PerPage, and returns self.
+// The bounds are checked by Validate and again before the query is executed.
+func (pager *Pager) Limits(limits Limits) *Pager {
+	pager.limits = limits
+	return pager
+}
+
+// OutOfRange set the policy used when the page is greater than the total page, and returns self.
+// Default is OutOfRangeEmpty.
+func (pager *Pager) OutOfRange(policy OutOfRangePolicy) *Pager {
+	pager.outOfRange = policy
+	return pager
+}
+
+// ConcurrentCount set whether to count the records concurrently with the query, and returns self.
+// The count is executed on another session that has the same context, and it is joined after the query.
+//
+// It MUST NOT be enabled in a transaction, because a transaction cannot execute queries concurrently.
+// It is ignored when the OutOfRange policy needs the count before the query.
+func (pager *Pager) ConcurrentCount(enabled bool) *Pager {
+	pager.concurrentCount = enabled
+	return pager
+}
+
+// CountDistinct set whether to count the distinct primary keys of the model in the queries with JOIN, and returns self.
+// By default, the joined rows are counted, because the pages are made of them.
+//
+// It is useful when the joined tables are used only for the conditions, and the records of the model are not duplicated.
+// It is ignored when the query has GROUP BY or DISTINCT, or the model does not have a primary key.
+func (pager *Pager) CountDistinct(enabled bool) *Pager {
+	pager.countDistinct = enabled
+	return pager
 }
 
 // Scope returns a GORM scope.
+// The result of the query can be read from the Pager, but it is overwritten by other queries executed with Scope.
+// Please use ScopeWithResult if you share the Pager.
 func (pager *Pager) Scope() func(db *gorm.DB) *gorm.DB {
+	return pager.scope(nil)
+}
+
+// ScopeWithResult returns a GORM scope that writes the result of the query to the result.
+func (pager *Pager) ScopeWithResult(result *PagerResult) func(db *gorm.DB) *gorm.DB {
+	if result == nil {
+		panic("result must not be nil")
+	}
+	return pager.scope(result)
+}
+
+func (pager *Pager) scope(result *PagerResult) func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
-		db = db.InstanceSet("pageboy:pager", pager)
-		return db.Offset((pager.Page - 1) * pager.PerPage).Limit(pager.PerPage)
+		query := &pagerQuery{pager: pager, result: result}
+		if result == nil {
+			query.result = &PagerResult{}
+			query.isLast = true
+		}
+		db = db.InstanceSet("pageboy:pager", query)
+		// NOTE: the error of the limits is returned by the query.
+		page, perPage, _ := pager.enforcedPage()
+		return db.Offset((page - 1) * perPage).Limit(perPage)
 	}
 }
 
-func pagerHandleBeforeQuery(db *gorm.DB) {
+// wait waits for the count, and returns the error of it.
+func (query *pagerQuery) wait(cancel bool) error {
+	if query.waitCount == nil {
+		return nil
+	}
+	wait := query.waitCount
+	query.waitCount = nil
+	return wait(cancel)
+}
+
+// clone returns a copy of the configuration.
+func (pager *Pager) clone() *Pager {
+	return &Pager{
+		Page:            pager.Page,
+		PerPage:         pager.PerPage,
+		Anchor:          pager.Anchor,
+		rawOrders:       pager.rawOrders,
+		orders:          pager.orders,
+		nullsOrders:     pager.nullsOrders,
+		columns:         pager.columns,
+		limits:          pager.limits,
+		outOfRange:      pager.outOfRange,
+		concurrentCount: pager.concurrentCount,
+		countDistinct:   pager.countDistinct,
+		paramNames:      pager.paramNames,
+	}
+}
+
+func (pager *Pager) getParamNames() ParamNames {
+	if pager.paramNames != nil {
+		return *pager.paramNames
+	}
+	return DefaultParamNames
+}
+
+func (pager *Pager) lastResult() *PagerResult {
+	if result, ok := pager.last.Load().(*PagerResult); ok {
+		return result
+	}
+	return &PagerResult{page: pager.Page, perPage: pager.PerPage}
+}
+
+func (pager *Pager) baseOrder() pbc.Order {
+	if len(pager.orders) > 0 {
+		return pager.orders[0]
+	}
+	return pbc.ASC
+}
+
+// anchorValues returns the values of the anchor, if it is an anchor of the previous page.
+func (pager *Pager) anchorValues(ty reflect.Type, page int) ([]interface{}, bool) {
+	if pager.Anchor == "" || len(pager.columns) == 0 {
+		return nil, false
+	}
+	segments := pbc.NewCursorSegments(pager.Anchor)
+	if len(segments) != len(pager.columns)+1 || segments[0].Int64() != int64(page-1) {
+		return nil, false
+	}
+	// NOTE: the anchor that cannot be decoded is ignored, because OFFSET returns the same page.
+	if isUntypedModel(ty) && !isIntegerSegments(segments[1:]) {
+		return nil, false
+	}
+	return segments[1:].Interface(ty, pager.columns...), true
+}
+
+func getPagerQuery(db *gorm.DB) (*pagerQuery, bool) {
 	value, ok := db.InstanceGet("pageboy:pager")
 	if !ok {
-		return
+		return nil, false
+	}
+	query, ok := value.(*pagerQuery)
+	if !ok {
+		return nil, false
 	}
-	pager, ok := value.(*Pager)
+	return query, true
+}
+
+func pagerHandleBeforeQuery(db *gorm.DB) {
+	query, ok := getPagerQuery(db)
 	if !ok {
 		return
 	}
-	if pager.totalCount > 0 {
+	pager, result := query.pager, query.result
+
+	result.page, result.perPage, _ = pager.enforcedPage()
+	result.totalCount = 0
+
+	if err := pager.enforceLimits(db); err != nil {
+		db.AddError(err)
+		return
+	}
+
+	// NOTE: the policies except OutOfRangeEmpty need the count before the query.
+	if pager.concurrentCount && pager.outOfRange == OutOfRangeEmpty {
+		query.waitCount = startCount(db, &result.totalCount, pager.countDistinct, true)
+	} else if err := startCount(db, &result.totalCount, pager.countDistinct, false)(false); err != nil {
+		db.AddError(err)
+		return
+	}
+
+	if pager.outOfRange != OutOfRangeEmpty && result.page > 1 && result.page > result.totalPage() {
+		lastPage := result.totalPage()
+		switch pager.outOfRange {
+		case OutOfRangeLastPage:
+			if lastPage < 1 {
+				lastPage = 1
+			}
+			result.page = lastPage
+			if offset := (lastPage - 1) * result.perPage; offset > 0 {
+				db.Offset(offset)
+			} else {
+				db.Offset(-1)
+			}
+		case OutOfRangeError:
+			db.AddError(ErrPageOutOfRange)
+			return
+		}
+	}
+
+	if len(pager.columns) == 0 {
 		return
 	}
 
-	tx := db.Session(&gorm.Session{})
+	columns := quoteColumns(db, pager.columns)
+	if values, ok := pager.anchorValues(getModelType(db), result.page); ok {
+		isBefore := pager.baseOrder() == pbc.DESC
+		comparisons := makeComparisons(len(pager.columns), pager.orders, pager.baseOrder(), isBefore)
+		db = pbc.MakeComparisonScope(columns, comparisons, pager.nullsOrders, values)(db)
+		// NOTE: the records before the anchor are already excluded by the where clause.
+		db = db.Offset(-1)
+	}
+	db.Order(pbc.OrderClauseBuilder(columns...)(pager.rawOrders...))
+}
+
+// enforceLimits applies the limits to LIMIT and OFFSET clauses, because they may be overwritten after the scope.
+func (pager *Pager) enforceLimits(db *gorm.DB) error {
+	limits := pager.limits
+	limit, _ := getLimitClause(db)
+
+	perPage := math.MaxInt
+	if limit.Limit != nil && *limit.Limit >= 0 {
+		perPage = *limit.Limit
+	}
+	if limits.MaxPerPage > 0 {
+		newPerPage, err := limits.enforce("PerPage", perPage, limits.MaxPerPage)
+		if err != nil {
+			return err
+		}
+		if newPerPage != perPage {
+			perPage = newPerPage
+			db.Limit(perPage)
+		}
+	}
+
+	offset := limit.Offset
+	if limits.MaxPage > 0 && perPage > 0 && perPage != math.MaxInt {
+		page := offset/perPage + 1
+		newPage, err := limits.enforce("Page", page, limits.MaxPage)
+		if err != nil {
+			return err
+		}
+		if newPage != page {
+			offset = (newPage - 1) * perPage
+		}
+	}
+	if limits.MaxOffset > 0 {
+		newOffset, err := limits.enforce("Offset", offset, limits.MaxOffset)
+		if err != nil {
+			return err
+		}
+		offset = newOffset
+	}
+	if offset != limit.Offset {
+		if offset == 0 {
+			// NOTE: Offset(0) does not overwrite the offset.
+			db.Offset(-1)
+		} else {
+			db.Offset(offset)
+		}
+	}
+	return nil
+}
+
+// newCountQuery returns a query to count the records of db.
+// The returned query does not share the statement with db, so it can be executed concurrently.
+// If distinctJoins is true, the queries with JOIN count the distinct primary keys of the model.
+func newCountQuery(db *gorm.DB, ctx context.Context, distinctJoins bool) *gorm.DB {
+	tx := db.Session(&gorm.Session{Context: ctx})
 	clauses := tx.Statement.Clauses
 	newClauses := make(map[string]clause.Clause)
 	orderKey := (&clause.OrderBy{}).Name()
@@ -87,16 +416,96 @@ func pagerHandleBeforeQuery(db *gorm.DB) {
 	tx.Statement.Clauses = newClauses
 
 	// NOTE: preload must be deleted.
-	preloads := tx.Statement.Preloads
 	tx.Statement.Preloads = map[string][]interface{}{}
 
-	tx.Model(db.Statement.Dest).Count(&pager.totalCount)
+	// NOTE: the queries with JOIN count the joined rows, because the pages are made of them.
+	_, isGrouped := clauses[(&clause.GroupBy{}).Name()]
+	if isGrouped || tx.Statement.Distinct {
+		// NOTE: COUNT(*) returns the count of each group, or counts the duplicated records.
+		// So it counts the records of the query that is executed as the derived table.
+		query := tx.Model(db.Statement.Model)
+		return tx.Session(&gorm.Session{NewDB: true}).Table("(?) AS pageboy_count", query)
+	}
+	if distinctJoins && len(tx.Statement.Joins) > 0 {
+		if sch := tx.Statement.Schema; sch != nil && sch.PrioritizedPrimaryField != nil && tx.Statement.Table != "" {
+			// NOTE: it is executed as COUNT(DISTINCT(table.pk)).
+			return tx.Model(db.Statement.Model).Distinct(tx.Statement.Table + "." + sch.PrioritizedPrimaryField.DBName)
+		}
+	}
+	return tx.Model(db.Statement.Model)
+}
+
+// startCount starts to count the records of db, and returns a function that waits for the result.
+// If concurrent is false, the count is finished before it returns.
+func startCount(db *gorm.DB, count *int64, distinctJoins bool, concurrent bool) func(cancel bool) error {
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if !concurrent {
+		err := newCountQuery(db, ctx, distinctJoins).Count(count).Error
+		return func(bool) error {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	query := newCountQuery(db, ctx, distinctJoins)
+	done := make(chan error, 1)
+	go func() {
+		done <- query.Count(count).Error
+	}()
+	return func(isCanceled bool) error {
+		if isCanceled {
+			cancel()
+		}
+		err := <-done
+		cancel()
+		return err
+	}
+}
+
+func pagerHandleAfterQuery(db *gorm.DB) {
+	query, ok := getPagerQuery(db)
+	if !ok {
+		return
+	}
+
+	if err := query.wait(db.Error != nil); err != nil && db.Error == nil {
+		db.AddError(err)
+	}
+}
+
+func pagerHandleQuery(db *gorm.DB) {
+	query, ok := getPagerQuery(db)
+	if !ok {
+		return
+	}
+	pager, result := query.pager, query.result
 
-	tx.Statement.Preloads = preloads
-	tx.Statement.Clauses = clauses
+	result.nextAnchor = ""
+	if query.isLast {
+		defer pager.last.Store(result)
+	}
+
+	if db.Error != nil || len(pager.columns) == 0 {
+		return
+	}
+	results := db.Statement.ReflectValue
+	if !(results.Kind() == reflect.Array || results.Kind() == reflect.Slice) {
+		return
+	}
+
+	if length := results.Len(); length > 0 {
+		values := getValuesFromColumns(results.Index(length-1), pager.columns...)
+		result.nextAnchor = pbc.FormatCursorString(append([]interface{}{result.page}, values...)...)
+	}
 }
 
 func registerPagerCallbacks(db *gorm.DB) {
 	q := db.Callback().Query()
 	q.Before("gorm:query").Replace("pageboy:pager:before_query", pagerHandleBeforeQuery)
+	q.After("gorm:query").Replace("pageboy:pager:after_query", pagerHandleAfterQuery)
+	q.Replace("pageboy:pager:handle_query", pagerHandleQuery)
 }
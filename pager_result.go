@@ -0,0 +1,76 @@
+package pageboy
+
+import (
+	"math"
+	"net/url"
+	"strconv"
+
+	pbc "github.com/soranoba/pageboy/v4/core"
+)
+
+// PagerResult is the result of a query paginated by Pager.
+// See: Pager.ScopeWithResult
+type PagerResult struct {
+	page       int
+	perPage    int
+	totalCount int64
+	nextAnchor pbc.CursorString
+}
+
+// Summary returns a PagerSummary.
+func (result *PagerResult) Summary() *PagerSummary {
+	return &PagerSummary{
+		Page:       result.page,
+		PerPage:    result.perPage,
+		TotalCount: result.totalCount,
+		TotalPage:  result.totalPage(),
+		NextAnchor: result.nextAnchor,
+	}
+}
+
+// Links returns the links to the first, previous, next and last pages.
+// Prev and Next are omitted when the page does not exist.
+func (result *PagerResult) Links(base *url.URL) *PagingLinks {
+	return result.links(base, DefaultParamNames)
+}
+
+func (result *PagerResult) links(base *url.URL, names ParamNames) *PagingLinks {
+	links := &PagingLinks{}
+	if base == nil || result.perPage < 1 {
+		return links
+	}
+
+	page := func(number int) string {
+		return withQuery(base, func(query url.Values) {
+			query.Set(names.Page, strconv.Itoa(number))
+			query.Set(names.PerPage, strconv.Itoa(result.perPage))
+			if names.Anchor == "" {
+				return
+			}
+			// NOTE: the anchor is only valid for the next page.
+			if number == result.page+1 && result.nextAnchor != "" {
+				query.Set(names.Anchor, string(result.nextAnchor))
+			} else {
+				query.Del(names.Anchor)
+			}
+		})
+	}
+	lastPage := result.totalPage()
+	if lastPage < 1 {
+		lastPage = 1
+	}
+
+	links.First = page(1)
+	if result.page > 1 && result.page-1 <= lastPage {
+		links.Prev = page(result.page - 1)
+	}
+	if result.page < lastPage {
+		links.Next = page(result.page + 1)
+	}
+	links.Last = page(lastPage)
+	return links
+}
+
+func (result *PagerResult) totalPage() int {
+	return int(math.Ceil(float64(result.totalCount) / float64(result.perPage)))
+}
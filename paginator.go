@@ -0,0 +1,92 @@
+package pageboy
+
+import (
+	"net/url"
+
+	"gorm.io/gorm"
+)
+
+// Paginator is the common interface of Cursor and Pager.
+// It can be used by the endpoints that switch the pagination at runtime. See: DetectPaginator
+type Paginator interface {
+	// Validate returns an error when the values are invalid.
+	Validate() error
+	// Scope returns a GORM scope.
+	Scope() func(db *gorm.DB) *gorm.DB
+	// PaginationSummary returns the summary of the last query. (*CursorSummary or *PagerSummary)
+	//
+	// NOTE: it cannot be `Summary() any`, because the Summary of Cursor and Pager return the concrete types.
+	PaginationSummary() any
+	// Links returns the links of the pages from the base.
+	Links(base *url.URL) *PagingLinks
+}
+
+var (
+	_ Paginator = (*Cursor)(nil)
+	_ Paginator = (*Pager)(nil)
+)
+
+// PagingLinks is the links of the pages.
+// Unavailable links are omitted.
+type PagingLinks struct {
+	First string `json:"first,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+	Next  string `json:"next,omitempty"`
+	Last  string `json:"last,omitempty"`
+}
+
+// DetectPaginator returns a Paginator that the values are bound by DefaultParamNames.
+// See: ParamNames.DetectPaginator
+func DetectPaginator(values url.Values, cursor *Cursor, pager *Pager) (Paginator, error) {
+	return DefaultParamNames.DetectPaginator(values, cursor, pager)
+}
+
+// DetectPaginator detects which of the cursor and the pager the values are for, and returns a copy of it that the values are bound.
+// The cursor and the pager are the configurations such as Paginate and Order, and nil means that it is not accepted.
+//
+// It returns a ValidationError when the values have the parameters of both, or the parameters that are not accepted.
+// When the values have neither, it returns the cursor if it is not nil, otherwise the pager.
+// Links of the returned Paginator use the names.
+// The parameters that have the same name for both (e.g. `page[size]` of JSONAPIParamNames) are not used to detect.
+func (names ParamNames) DetectPaginator(values url.Values, cursor *Cursor, pager *Pager) (Paginator, error) {
+	cursorNames := []string{names.Before, names.After, names.Until, names.Since, names.Around, names.Limit, names.Reverse}
+	pagerNames := []string{names.Page, names.PerPage, names.Anchor}
+
+	find := func(names []string, others []string) string {
+		for _, name := range names {
+			if _, ok := lookupValue(values, name); ok && !containsString(others, name) {
+				return name
+			}
+		}
+		return ""
+	}
+	cursorParam := find(cursorNames, pagerNames)
+	pagerParam := find(pagerNames, cursorNames)
+
+	switch {
+	case cursorParam != "" && pagerParam != "":
+		return nil, &ValidationError{Field: pagerParam, Message: "cannot be used with " + cursorParam}
+	case cursorParam != "" && cursor == nil:
+		return nil, &ValidationError{Field: cursorParam, Message: "is not supported"}
+	case pagerParam != "" && pager == nil:
+		return nil, &ValidationError{Field: pagerParam, Message: "is not supported"}
+	}
+
+	if pagerParam == "" && cursor != nil {
+		c := cursor.clone()
+		c.paramNames = &names
+		if err := names.bindCursor(values, c); err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+	if pager == nil {
+		return nil, &ValidationError{Field: "Paginator", Message: "is required"}
+	}
+	p := pager.clone()
+	p.paramNames = &names
+	if err := names.bindPager(values, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
@@ -0,0 +1,106 @@
+package pageboy
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// ErrModelRequired is an error returned when the model is not specified. (e.g. db.Model(&User{}))
+var ErrModelRequired = errors.New("model is required")
+
+// Partition splits the range of the cursor into k disjoint ranges that have almost the same number of records,
+// and returns cursors of each range in the order of the cursor.
+// Each cursor is bounded by After and Before, so they can be read concurrently. (See: EachPartition)
+//
+// The db MUST specify the model (e.g. db.Model(&User{})), and the columns of the cursor MUST be unique.
+// It returns fewer cursors than k when the number of records is less than k.
+func Partition(db *gorm.DB, cursor *Cursor, k int) ([]*Cursor, error) {
+	if k < 1 {
+		return nil, &ValidationError{Field: "k", Message: "must be greater than 0"}
+	}
+	cursor = cursor.clone()
+	// NOTE: each range is read from the start of the order.
+	cursor.fill = FillDefault
+	if err := cursor.Validate(); err != nil {
+		return nil, err
+	}
+	if db.Statement.Model == nil {
+		return nil, ErrModelRequired
+	}
+	ty := reflect.TypeOf(db.Statement.Model)
+	for ty.Kind() == reflect.Ptr || ty.Kind() == reflect.Array || ty.Kind() == reflect.Slice {
+		ty = ty.Elem()
+	}
+
+	var count int64
+	if err := db.Session(&gorm.Session{}).Scopes(cursor.countScope()).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count < int64(k) {
+		k = int(count)
+	}
+
+	cursors := make([]*Cursor, 0, k)
+	current := cursor.clone()
+	for i := 1; i < k; i++ {
+		// NOTE: it reads the last record of the previous range and the first record of the next range.
+		offset := int(int64(i)*count/int64(k)) - 1
+		probe := cursor.clone()
+		probe.Limit = 2
+		probe.itemCursors = true
+
+		rows := reflect.New(reflect.SliceOf(ty))
+		result := &CursorResult{}
+		if err := db.Session(&gorm.Session{}).Offset(offset).Scopes(probe.ScopeWithResult(result)).Find(rows.Interface()).Error; err != nil {
+			return nil, err
+		}
+		if len(result.itemCursors) < 2 {
+			// the records have been deleted after counting.
+			break
+		}
+
+		last, first := result.itemCursors[0], result.itemCursors[1]
+
+		head := current.clone()
+		if head.isForward() {
+			head.Before, head.Until = first, ""
+		} else {
+			head.After, head.Since = first, ""
+		}
+		cursors = append(cursors, head)
+		current = current.next(last)
+	}
+	return append(cursors, current), nil
+}
+
+// EachPartition calls fn with each batch of the records read by Each for each cursor concurrently.
+// fn is called from multiple goroutines, so it MUST be goroutine-safe.
+// If fn returns an error, it cancels the others and returns the error.
+func EachPartition[T any](ctx context.Context, db *gorm.DB, cursors []*Cursor, fn func(batch []T) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg   sync.WaitGroup
+		once sync.Once
+		err  error
+	)
+	for _, cursor := range cursors {
+		wg.Add(1)
+		go func(cursor *Cursor) {
+			defer wg.Done()
+			if e := Each(ctx, db, cursor, fn); e != nil {
+				once.Do(func() {
+					err = e
+					cancel()
+				})
+			}
+		}(cursor)
+	}
+	wg.Wait()
+	return err
+}
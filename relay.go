@@ -0,0 +1,201 @@
+package pageboy
+
+import (
+	"errors"
+
+	pbc "github.com/soranoba/pageboy/v4/core"
+	"gorm.io/gorm"
+)
+
+// RelayArgs is the arguments of a connection of GraphQL Relay.
+// See: https://relay.dev/graphql/connections.htm
+type RelayArgs struct {
+	First  *int             `json:"first"`
+	After  pbc.CursorString `json:"after"`
+	Last   *int             `json:"last"`
+	Before pbc.CursorString `json:"before"`
+}
+
+// Connection is a connection of GraphQL Relay.
+type Connection[T any] struct {
+	Edges    []Edge[T] `json:"edges"`
+	PageInfo PageInfo  `json:"pageInfo"`
+}
+
+// Edge is an edge of Connection.
+type Edge[T any] struct {
+	Cursor pbc.CursorString `json:"cursor"`
+	Node   T                `json:"node"`
+}
+
+// PageInfo is the pagination information of Connection.
+// StartCursor and EndCursor are nil when the edges are empty.
+type PageInfo struct {
+	HasNextPage     bool              `json:"hasNextPage"`
+	HasPreviousPage bool              `json:"hasPreviousPage"`
+	StartCursor     *pbc.CursorString `json:"startCursor"`
+	EndCursor       *pbc.CursorString `json:"endCursor"`
+}
+
+// Validate returns an error when the RelayArgs is invalid.
+func (args *RelayArgs) Validate() error {
+	if args.First != nil && args.Last != nil {
+		return &ValidationError{Field: "Last", Message: "cannot be used with First"}
+	}
+	if args.First != nil && *args.First < 1 {
+		return &ValidationError{Field: "First", Message: "must be greater than 0"}
+	}
+	if args.Last != nil && *args.Last < 1 {
+		return &ValidationError{Field: "Last", Message: "must be greater than 0"}
+	}
+	if args.After != "" && !args.After.Validate() {
+		return &ValidationError{Field: "After", Message: "is invalid"}
+	}
+	if args.Before != "" && !args.Before.Validate() {
+		return &ValidationError{Field: "Before", Message: "is invalid"}
+	}
+	return nil
+}
+
+// FindConnection finds the records by the cursor with the arguments of GraphQL Relay, and returns them as Connection.
+// The order and the columns are specified by the cursor, and the Limit of the cursor is used when First and Last are nil.
+// After and Before of the arguments are positions in the order of the cursor, and `last N` reads the records in reverse.
+//
+//	conn, err := pageboy.FindConnection[*User](db, pageboy.NewCursor().Paginate("CreatedAt", "ID").Order("DESC", "DESC"), args)
+func FindConnection[T any](db *gorm.DB, cursor *Cursor, args RelayArgs) (*Connection[T], error) {
+	if err := args.Validate(); err != nil {
+		return nil, err
+	}
+
+	isLast := args.Last != nil
+	c := cursor.clone()
+	// NOTE: the arguments are sent by the clients, so the positions may not have the values of the columns.
+	if args.After != "" && !c.isValidPosition(args.After) {
+		return nil, &ValidationError{Field: "After", Message: "is invalid"}
+	}
+	if args.Before != "" && !c.isValidPosition(args.Before) {
+		return nil, &ValidationError{Field: "Before", Message: "is invalid"}
+	}
+	c.Reverse = false
+	c.fill = FillDefault
+	c.itemCursors = true
+	c.setRange(args.After, args.Before)
+	if isLast {
+		c.Limit = *args.Last
+		c.Reverse = true
+	} else if args.First != nil {
+		c.Limit = *args.First
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	items := make([]T, 0, c.Limit)
+	result := &CursorResult{}
+	if err := db.Session(&gorm.Session{}).Scopes(c.ScopeWithResult(result)).Find(&items).Error; err != nil {
+		return nil, relayPositionError(err, c, args)
+	}
+	cursors := result.GetItemCursors()
+	if isLast {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+			cursors[i], cursors[j] = cursors[j], cursors[i]
+		}
+	}
+
+	conn := &Connection[T]{Edges: make([]Edge[T], len(items))}
+	for i, item := range items {
+		conn.Edges[i] = Edge[T]{
+			Cursor: cursors[i],
+			Node:   item,
+		}
+	}
+	if len(conn.Edges) > 0 {
+		conn.PageInfo.StartCursor = &conn.Edges[0].Cursor
+		conn.PageInfo.EndCursor = &conn.Edges[len(conn.Edges)-1].Cursor
+	}
+
+	// NOTE: it probes the opposite side of the records that is not detected by hasMore.
+	if isLast {
+		conn.PageInfo.HasPreviousPage = result.HasMore()
+		position := args.Before
+		if conn.PageInfo.EndCursor != nil {
+			position = *conn.PageInfo.EndCursor
+		}
+		if position != "" {
+			probe := c.clone()
+			probe.Reverse = false
+			probe.setRange(position, "")
+			ok, err := existsRecords[T](db, probe)
+			if err != nil {
+				return nil, err
+			}
+			conn.PageInfo.HasNextPage = ok
+		}
+	} else {
+		conn.PageInfo.HasNextPage = result.HasMore()
+		position := args.After
+		if conn.PageInfo.StartCursor != nil {
+			position = *conn.PageInfo.StartCursor
+		}
+		if position != "" {
+			probe := c.clone()
+			probe.Reverse = false
+			probe.setRange("", position)
+			probe.Reverse = true
+			ok, err := existsRecords[T](db, probe)
+			if err != nil {
+				return nil, err
+			}
+			conn.PageInfo.HasPreviousPage = ok
+		}
+	}
+	return conn, nil
+}
+
+// setRange sets the range of records by the positions in the order of the cursor that is not reversed.
+func (cursor *Cursor) setRange(after pbc.CursorString, before pbc.CursorString) {
+	cursor.Since, cursor.Until = "", ""
+	if cursor.isForward() {
+		cursor.After, cursor.Before = after, before
+	} else {
+		cursor.After, cursor.Before = before, after
+	}
+}
+
+// existsRecords returns true if it exists some records in the range of the cursor.
+func existsRecords[T any](db *gorm.DB, cursor *Cursor) (bool, error) {
+	cursor.Limit = 1
+	items := make([]T, 0, 1)
+	if err := db.Session(&gorm.Session{}).Scopes(cursor.ScopeWithResult(&CursorResult{})).Find(&items).Error; err != nil {
+		return false, err
+	}
+	return len(items) > 0, nil
+}
+
+// relayPositionError returns the error that the field is the argument, when the position of the argument is invalid.
+// NOTE: After and Before of the arguments are moved to the other fields of the cursor by the order.
+func relayPositionError(err error, cursor *Cursor, args RelayArgs) error {
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		return err
+	}
+
+	var position pbc.CursorString
+	switch validationErr.Field {
+	case "Before":
+		position = cursor.Before
+	case "After":
+		position = cursor.After
+	default:
+		return err
+	}
+
+	switch {
+	case position != "" && position == args.After:
+		return &ValidationError{Field: "After", Message: validationErr.Message}
+	case position != "" && position == args.Before:
+		return &ValidationError{Field: "Before", Message: validationErr.Message}
+	}
+	return err
+}
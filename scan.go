@@ -0,0 +1,60 @@
+package pageboy
+
+import (
+	"reflect"
+
+	"gorm.io/gorm"
+)
+
+// Scan scans the records paginated by Cursor or Pager into dest, like gorm.DB.Scan.
+// gorm.DB.Scan does not execute the Query callbacks, so the scopes of pageboy need this function instead of it.
+// The values of cursors and anchors are read from the fields of dest, so it MUST have the pagination target columns.
+//
+//	var users []UserWithGroupName
+//	err := pageboy.Scan(db.Model(&User{}).Select("users.*, groups.name AS group_name").Joins("...").Scopes(cursor.Scope()), &users).Error
+//
+// NOTE: gorm.DB.Row and gorm.DB.Rows are not supported, because the records must be read to detect whether the next page exists.
+func Scan(db *gorm.DB, dest interface{}) *gorm.DB {
+	tx := db.InstanceSet("pageboy:scan", true)
+	tx.Statement.Dest = dest
+
+	if rows, err := tx.Rows(); err == nil {
+		if rows.Next() {
+			tx.ScanRows(rows, dest)
+		} else {
+			tx.RowsAffected = 0
+			tx.Statement.Dest = dest
+			tx.Statement.ReflectValue = reflect.Indirect(reflect.ValueOf(dest))
+			if results := tx.Statement.ReflectValue; results.Kind() == reflect.Slice {
+				results.Set(reflect.MakeSlice(results.Type(), 0, 0))
+			}
+			tx.AddError(rows.Err())
+		}
+		tx.AddError(rows.Close())
+	}
+
+	// NOTE: they are the same as the handlers after gorm:query.
+	cursorHandleAfterQuery(tx)
+	pagerHandleAfterQuery(tx)
+	cursorHandleQuery(tx)
+	pagerHandleQuery(tx)
+	return tx
+}
+
+func isScan(db *gorm.DB) bool {
+	value, ok := db.InstanceGet("pageboy:scan")
+	return ok && value == true
+}
+
+func scanHandleBeforeRow(db *gorm.DB) {
+	if !isScan(db) {
+		return
+	}
+	cursorHandleBeforeQuery(db)
+	pagerHandleBeforeQuery(db)
+}
+
+func registerScanCallbacks(db *gorm.DB) {
+	r := db.Callback().Row()
+	r.Before("gorm:row").Replace("pageboy:scan:before_row", scanHandleBeforeRow)
+}
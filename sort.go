@@ -0,0 +1,132 @@
+package pageboy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SortField is a field that clients can sort by.
+type SortField struct {
+	// Column is the name of the field of the model. (e.g. CreatedAt)
+	Column string
+	// Order is the order when the sort key has no sign. "ASC" or "DESC". (default: "ASC")
+	Order string
+	// Nulls is where NULLs are placed. "NULLS FIRST", "NULLS LAST" or empty.
+	Nulls string
+}
+
+// Sorts is an allowlist of sort keys that clients can specify, such as `?sort=-created_at,name`.
+// It converts the sort spec into the configuration of Paginate and Order, and the primary key is always appended as a tie-breaker.
+//
+//	var userSorts = pageboy.NewSorts("ID").
+//		Add("created_at", pageboy.SortField{Column: "CreatedAt", Order: "DESC"}).
+//		Add("name", pageboy.SortField{Column: "Name", Nulls: "NULLS LAST"})
+type Sorts struct {
+	primaryKey  string
+	fields      map[string]SortField
+	defaultSpec string
+}
+
+// NewSorts returns a Sorts that uses the primaryKey as a tie-breaker.
+func NewSorts(primaryKey string) *Sorts {
+	return &Sorts{
+		primaryKey: primaryKey,
+		fields:     map[string]SortField{},
+	}
+}
+
+// Add adds the sort key, and returns self.
+func (sorts *Sorts) Add(key string, field SortField) *Sorts {
+	sorts.fields[key] = field
+	return sorts
+}
+
+// Default set the sort spec used when the spec is empty, and returns self.
+func (sorts *Sorts) Default(spec string) *Sorts {
+	sorts.defaultSpec = spec
+	return sorts
+}
+
+// Parse returns the columns and the orders of the sort spec.
+// The spec is comma-separated keys, and the key prefixed by `-` is DESC and by `+` is ASC.
+// It returns a ValidationError when the spec has unknown keys or duplicated keys.
+//
+// The whitespaces around each key are ignored, such as `-created_at, name`.
+//
+// NOTE: `+` is decoded as a space in the query string, so the space at the beginning of the spec is treated as `+`.
+func (sorts *Sorts) Parse(spec string) ([]string, []string, error) {
+	if strings.TrimSpace(spec) == "" {
+		spec = sorts.defaultSpec
+	}
+
+	var columns, orders []string
+	used := map[string]bool{}
+	for i, rawKey := range strings.Split(spec, ",") {
+		key := strings.TrimSpace(rawKey)
+		if key == "" {
+			continue
+		}
+
+		var order string
+		switch {
+		case strings.HasPrefix(key, "-"):
+			order, key = "DESC", key[1:]
+		case strings.HasPrefix(key, "+"):
+			order, key = "ASC", key[1:]
+		case i == 0 && strings.HasPrefix(rawKey, " "):
+			order = "ASC"
+		}
+		key = strings.TrimSpace(key)
+
+		field, ok := sorts.fields[key]
+		if !ok {
+			return nil, nil, &ValidationError{Field: "sort", Message: fmt.Sprintf("has an unknown key `%s`", key)}
+		}
+		if used[field.Column] {
+			return nil, nil, &ValidationError{Field: "sort", Message: fmt.Sprintf("has a duplicated key `%s`", key)}
+		}
+		used[field.Column] = true
+
+		if order == "" {
+			order = strings.ToUpper(field.Order)
+			if order != "DESC" {
+				order = "ASC"
+			}
+		}
+		if field.Nulls != "" {
+			order += " " + strings.ToUpper(field.Nulls)
+		}
+		columns = append(columns, field.Column)
+		orders = append(orders, order)
+	}
+
+	if !used[sorts.primaryKey] {
+		order := "ASC"
+		if len(orders) > 0 && strings.HasPrefix(orders[0], "DESC") {
+			order = "DESC"
+		}
+		columns = append(columns, sorts.primaryKey)
+		orders = append(orders, order)
+	}
+	return columns, orders, nil
+}
+
+// ApplyCursor set Paginate and Order of the cursor by the sort spec.
+func (sorts *Sorts) ApplyCursor(cursor *Cursor, spec string) error {
+	columns, orders, err := sorts.Parse(spec)
+	if err != nil {
+		return err
+	}
+	cursor.Paginate(columns...).Order(orders...)
+	return nil
+}
+
+// ApplyPager set Paginate and Order of the pager by the sort spec.
+func (sorts *Sorts) ApplyPager(pager *Pager, spec string) error {
+	columns, orders, err := sorts.Parse(spec)
+	if err != nil {
+		return err
+	}
+	pager.Paginate(columns...).Order(orders...)
+	return nil
+}
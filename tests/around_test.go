@@ -0,0 +1,71 @@
+package pageboy_test
+
+import (
+	"testing"
+
+	"github.com/soranoba/pageboy/v4"
+	pbc "github.com/soranoba/pageboy/v4/core"
+)
+
+func TestFindAround(t *testing.T) {
+	db := openDB()
+	assertNoError(t, db.Migrator().DropTable(&cursorModel{}))
+	assertNoError(t, db.AutoMigrate(&cursorModel{}))
+
+	for i := 0; i < 10; i++ {
+		assertNoError(t, db.Create(&cursorModel{}).Error)
+	}
+
+	ids := func(models []*cursorModel) []uint {
+		ids := make([]uint, 0)
+		for _, model := range models {
+			ids = append(ids, model.ID)
+		}
+		return ids
+	}
+
+	page, err := pageboy.FindAround[*cursorModel](db, (&pageboy.Cursor{Around: "5", Limit: 2}).Paginate("ID").Order(ASC))
+	assertNoError(t, err)
+	assertEqual(t, ids(page.Items), []uint{3, 4, 5, 6, 7})
+	assertEqual(t, page.NextBefore, pbc.CursorString("3"))
+	assertEqual(t, page.NextAfter, pbc.CursorString("7"))
+	assertEqual(t, page.HasMoreBefore, true)
+	assertEqual(t, page.HasMoreAfter, true)
+
+	page, err = pageboy.FindAround[*cursorModel](db, (&pageboy.Cursor{Around: "2", Limit: 3}).Paginate("ID").Order(ASC))
+	assertNoError(t, err)
+	assertEqual(t, ids(page.Items), []uint{1, 2, 3, 4, 5})
+	assertEqual(t, page.HasMoreBefore, false)
+	assertEqual(t, page.HasMoreAfter, true)
+
+	page, err = pageboy.FindAround[*cursorModel](db, (&pageboy.Cursor{Around: "8", Limit: 3}).Paginate("ID").Order(DESC))
+	assertNoError(t, err)
+	assertEqual(t, ids(page.Items), []uint{10, 9, 8, 7, 6, 5})
+	assertEqual(t, page.NextBefore, pbc.CursorString("5"))
+	assertEqual(t, page.NextAfter, pbc.CursorString("10"))
+	assertEqual(t, page.HasMoreBefore, true)
+	assertEqual(t, page.HasMoreAfter, false)
+
+	page, err = pageboy.FindAround[*cursorModel](db, (&pageboy.Cursor{Around: "4", Limit: 2, Reverse: true}).Paginate("ID").Order(ASC))
+	assertNoError(t, err)
+	assertEqual(t, ids(page.Items), []uint{6, 5, 4, 3, 2})
+	assertEqual(t, page.NextBefore, pbc.CursorString("2"))
+	assertEqual(t, page.NextAfter, pbc.CursorString("6"))
+	assertEqual(t, page.HasMoreBefore, true)
+	assertEqual(t, page.HasMoreAfter, true)
+
+	// the record at the position does not exist.
+	assertNoError(t, db.Delete(&cursorModel{}, 5).Error)
+	page, err = pageboy.FindAround[*cursorModel](db, (&pageboy.Cursor{Around: "5", Limit: 1}).Paginate("ID").Order(ASC))
+	assertNoError(t, err)
+	assertEqual(t, ids(page.Items), []uint{4, 6})
+
+	_, err = pageboy.FindAround[*cursorModel](db, (&pageboy.Cursor{Limit: 1}).Paginate("ID").Order(ASC))
+	assertError(t, err)
+	_, err = pageboy.FindAround[*cursorModel](db, (&pageboy.Cursor{Around: "1", After: "1", Limit: 1}).Paginate("ID").Order(ASC))
+	assertError(t, err)
+
+	var models []*cursorModel
+	cursor := (&pageboy.Cursor{Around: "1", Limit: 1}).Paginate("ID").Order(ASC)
+	assertEqual(t, db.Scopes(cursor.Scope()).Find(&models).Error, pageboy.ErrAroundRequiresFindAround)
+}
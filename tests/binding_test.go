@@ -0,0 +1,64 @@
+package pageboy_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/soranoba/pageboy/v4"
+)
+
+func TestCursorFromValues(t *testing.T) {
+	cursor, err := pageboy.CursorFromValues(url.Values{})
+	assertNoError(t, err)
+	assertEqual(t, cursor, pageboy.NewCursor())
+
+	cursor, err = pageboy.CursorFromRequest(httptest.NewRequest("GET", "/?before=10&after=1&limit=5&reverse=true", nil))
+	assertNoError(t, err)
+	assertEqual(t, string(cursor.Before), "10")
+	assertEqual(t, string(cursor.After), "1")
+	assertEqual(t, cursor.Limit, 5)
+	assertEqual(t, cursor.Reverse, true)
+
+	cursor, err = pageboy.JSONAPIParamNames.CursorFromValues(url.Values{"page[size]": {"3"}, "page[after]": {"2"}, "limit": {"5"}})
+	assertNoError(t, err)
+	assertEqual(t, cursor.Limit, 3)
+	assertEqual(t, string(cursor.After), "2")
+
+	var validationErr *pageboy.ValidationError
+	_, err = pageboy.CursorFromValues(url.Values{"limit": {"a"}})
+	assertEqual(t, errors.As(err, &validationErr), true)
+	assertEqual(t, validationErr.Field, "limit")
+
+	_, err = pageboy.CursorFromValues(url.Values{"reverse": {"a"}})
+	assertEqual(t, errors.As(err, &validationErr), true)
+	assertEqual(t, validationErr.Field, "reverse")
+}
+
+func TestPagerFromValues(t *testing.T) {
+	pager, err := pageboy.PagerFromValues(url.Values{})
+	assertNoError(t, err)
+	assertEqual(t, pager, pageboy.NewPager())
+
+	pager, err = pageboy.PagerFromRequest(httptest.NewRequest("GET", "/?page=3&per_page=20&anchor=1", nil))
+	assertNoError(t, err)
+	assertEqual(t, pager.Page, 3)
+	assertEqual(t, pager.PerPage, 20)
+	assertEqual(t, string(pager.Anchor), "1")
+
+	names := pageboy.DefaultParamNames
+	names.PerPage = "size"
+	pager, err = names.PagerFromValues(url.Values{"size": {"5"}, "per_page": {"20"}})
+	assertNoError(t, err)
+	assertEqual(t, pager.PerPage, 5)
+
+	var validationErr *pageboy.ValidationError
+	_, err = pageboy.PagerFromValues(url.Values{"page": {"1.5"}})
+	assertEqual(t, errors.As(err, &validationErr), true)
+	assertEqual(t, validationErr.Field, "page")
+
+	_, err = pageboy.JSONAPIParamNames.PagerFromValues(url.Values{"page[size]": {"x"}})
+	assertEqual(t, errors.As(err, &validationErr), true)
+	assertEqual(t, validationErr.Field, "page[size]")
+}
@@ -0,0 +1,91 @@
+package pageboy_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/soranoba/pageboy/v4"
+)
+
+func TestRunWithCheckpoint(t *testing.T) {
+	db := openDB()
+	assertNoError(t, db.Migrator().DropTable(&cursorModel{}, &pageboy.Checkpoint{}))
+	assertNoError(t, db.AutoMigrate(&cursorModel{}, &pageboy.Checkpoint{}))
+
+	for i := 0; i < 7; i++ {
+		assertNoError(t, db.Create(&cursorModel{}).Error)
+	}
+
+	stores := map[string]pageboy.CheckpointStore{
+		"file": &pageboy.FileCheckpointStore{Dir: t.TempDir()},
+		"gorm": &pageboy.GormCheckpointStore{DB: db},
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			cursor := (&pageboy.Cursor{Limit: 2}).Paginate("ID").Order(ASC)
+
+			position, err := store.Load(ctx, "backfill")
+			assertNoError(t, err)
+			assertEqual(t, string(position), "")
+
+			// crashes at the third batch.
+			errCrash := errors.New("crash")
+			var ids []uint
+			err = pageboy.RunWithCheckpoint(ctx, db, store, "backfill", cursor, func(batch []cursorModel) error {
+				if len(ids) == 4 {
+					return errCrash
+				}
+				for _, model := range batch {
+					ids = append(ids, model.ID)
+				}
+				return nil
+			})
+			assertEqual(t, err, errCrash)
+			assertEqual(t, ids, []uint{1, 2, 3, 4})
+
+			position, err = store.Load(ctx, "backfill")
+			assertNoError(t, err)
+			assertEqual(t, string(position), "4")
+
+			// resumes from the checkpoint.
+			err = pageboy.RunWithCheckpoint(ctx, db, store, "backfill", cursor, func(batch []cursorModel) error {
+				for _, model := range batch {
+					ids = append(ids, model.ID)
+				}
+				return nil
+			})
+			assertNoError(t, err)
+			assertEqual(t, ids, []uint{1, 2, 3, 4, 5, 6, 7})
+
+			// other jobs are independent.
+			position, err = store.Load(ctx, "other-job")
+			assertNoError(t, err)
+			assertEqual(t, string(position), "")
+			assertNoError(t, store.Save(ctx, "other-job", "2"))
+			position, err = store.Load(ctx, "other-job")
+			assertNoError(t, err)
+			assertEqual(t, string(position), "2")
+
+			position, err = store.Load(ctx, "backfill")
+			assertNoError(t, err)
+			assertEqual(t, string(position), "7")
+		})
+	}
+}
+
+func TestFileCheckpointStore_invalidJob(t *testing.T) {
+	ctx := context.Background()
+	store := &pageboy.FileCheckpointStore{Dir: t.TempDir()}
+
+	for _, job := range []string{"", ".", "..", "../backfill", "jobs/backfill", `jobs\backfill`} {
+		var validationErr *pageboy.ValidationError
+		_, err := store.Load(ctx, job)
+		if assertEqual(t, errors.As(err, &validationErr), true) {
+			assertEqual(t, validationErr.Field, "Job")
+		}
+		err = store.Save(ctx, job, "1")
+		assertEqual(t, errors.As(err, &validationErr), true)
+	}
+}
@@ -1,9 +1,12 @@
 package pageboy_test
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
+	"net/http"
 	"net/url"
 	"strconv"
 	"sync"
@@ -84,6 +87,24 @@ func TestCursorValidate(t *testing.T) {
 
 	cursor = &pageboy.Cursor{Before: "", After: "", Limit: 10}
 	assertNoError(t, cursor.Validate())
+
+	// limits
+	cursor = &pageboy.Cursor{Limit: 101}
+	assertError(t, cursor.Limits(pageboy.Limits{MaxLimit: 100}).Validate())
+
+	// the clamped limit is applied to the query, and the cursor is not changed.
+	db := openDB()
+	assertNoError(t, db.Migrator().DropTable(&cursorModel{}))
+	assertNoError(t, db.AutoMigrate(&cursorModel{}))
+
+	cursor = (&pageboy.Cursor{Limit: 101}).Paginate("ID").Order(ASC)
+	assertNoError(t, cursor.Limits(pageboy.Limits{MaxLimit: 100, Policy: pageboy.ClampOverLimit}).Validate())
+	assertEqual(t, cursor.Limit, 101)
+
+	var models []cursorModel
+	assertNoError(t, db.Scopes(cursor.Scope()).Find(&models).Error)
+	assertEqual(t, cursor.Summary().Limit, 100)
+	assertEqual(t, cursor.Limit, 101)
 }
 
 func TestCursorPaginateDESC(t *testing.T) {
@@ -1334,6 +1355,26 @@ func TestCursor_where_clause_is_ambiguous(t *testing.T) {
 	})
 }
 
+func TestCursorPaginateWithLimits(t *testing.T) {
+	db := openDB()
+	assertNoError(t, db.Migrator().DropTable(&cursorModel{}))
+	assertNoError(t, db.AutoMigrate(&cursorModel{}))
+
+	for i := 0; i < 5; i++ {
+		assertNoError(t, db.Create(&cursorModel{}).Error)
+	}
+
+	var models []*cursorModel
+	cursor := (&pageboy.Cursor{Limit: 2}).Limits(pageboy.Limits{MaxLimit: 2})
+	assertError(t, db.Scopes(cursor.Paginate("ID").Order(ASC).Scope(), limit(100)).Find(&models).Error)
+	assertError(t, db.Scopes(cursor.Paginate("ID").Order(ASC).Scope(), limit(-1)).Find(&models).Error)
+
+	cursor = (&pageboy.Cursor{Limit: 2}).Limits(pageboy.Limits{MaxLimit: 2, Policy: pageboy.ClampOverLimit})
+	assertNoError(t, db.Scopes(cursor.Paginate("ID").Order(ASC).Scope(), limit(100)).Find(&models).Error)
+	assertEqual(t, len(models), 2)
+	assertEqual(t, cursor.GetNextAfter(), pbc.FormatCursorString(models[1].ID))
+}
+
 func TestCursor_preload(t *testing.T) {
 	db := openDB()
 
@@ -1391,6 +1432,42 @@ func TestCursor_concurrency(t *testing.T) {
 	wg.Wait()
 }
 
+func TestCursor_shared(t *testing.T) {
+	db := openDB()
+	assertNoError(t, db.Migrator().DropTable(&cursorModel{}))
+	assertNoError(t, db.AutoMigrate(&cursorModel{}))
+
+	for i := 0; i < 5; i++ {
+		assertNoError(t, db.Create(&cursorModel{}).Error)
+	}
+
+	cursor := (&pageboy.Cursor{Limit: 2}).Paginate("ID").Order(ASC)
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			var models []cursorModel
+			result := &pageboy.CursorResult{}
+			tx := db.Session(&gorm.Session{})
+			if i%2 == 0 {
+				tx = tx.Where("id > ?", 3)
+			}
+			assertNoError(t, tx.Scopes(cursor.ScopeWithResult(result)).Find(&models).Error)
+			assertEqual(t, len(models), 2)
+			assertEqual(t, result.GetNextAfter(), pbc.FormatCursorString(models[1].ID))
+			assertEqual(t, result.HasMore(), i%2 == 1)
+
+			// It is only the last result, but it can be read concurrently.
+			assertNoError(t, db.Session(&gorm.Session{}).Scopes(cursor.Scope()).Find(&models).Error)
+			assertNotEqual(t, cursor.GetNextAfter(), pbc.CursorString(""))
+		}(i)
+	}
+	wg.Wait()
+}
+
 func ExampleCursor() {
 	db := openDB()
 
@@ -1433,3 +1510,311 @@ func ExampleCursor() {
 	// users[1].Name == "Alice"
 	// {"next":"https://localhost/path?after=18_1\u0026q=%E3%81%AF%E3%82%8D%E3%83%BC"}
 }
+
+func TestCursorItemCursors(t *testing.T) {
+	db := openDB()
+	assertNoError(t, db.Migrator().DropTable(&cursorModel{}))
+	assertNoError(t, db.AutoMigrate(&cursorModel{}))
+
+	now := time.Now().UTC().Truncate(time.Second)
+	for i := 0; i < 3; i++ {
+		assertNoError(t, db.Create(&cursorModel{Time: &now}).Error)
+	}
+
+	var models []cursorModel
+	cursor := (&pageboy.Cursor{Limit: 2}).Paginate("Time", "ID").Order(ASC, ASC).ItemCursors(true)
+	assertNoError(t, db.Scopes(cursor.Scope()).Find(&models).Error)
+	assertEqual(t, len(models), 2)
+	assertEqual(t, cursor.GetItemCursors(), []pbc.CursorString{
+		pageboy.CursorOf(models[0], "Time", "ID"),
+		pageboy.CursorOf(&models[1], "Time", "ID"),
+	})
+	assertEqual(t, cursor.GetItemCursors()[1], cursor.GetNextAfter())
+	assertEqual(t, pageboy.CursorOf(models[0], "ID"), pbc.FormatCursorString(models[0].ID))
+
+	// continues from the item.
+	var next []cursorModel
+	cursor = (&pageboy.Cursor{Limit: 2, After: pageboy.CursorOf(models[0], "Time", "ID")}).Paginate("Time", "ID").Order(ASC, ASC)
+	assertNoError(t, db.Scopes(cursor.Scope()).Find(&next).Error)
+	assertEqual(t, len(next), 2)
+	assertEqual(t, next[0].ID, models[1].ID)
+
+	// disabled by default.
+	assertEqual(t, len(cursor.GetItemCursors()), 0)
+}
+
+func TestCursorSinceUntil(t *testing.T) {
+	db := openDB()
+	assertNoError(t, db.Migrator().DropTable(&cursorModel{}))
+	assertNoError(t, db.AutoMigrate(&cursorModel{}))
+
+	subID := func(v uint) *uint { return &v }
+	for _, v := range []*uint{nil, subID(2), subID(1), nil, subID(2), subID(3)} {
+		assertNoError(t, db.Create(&cursorModel{SubID: v}).Error)
+	}
+
+	ids := func(models []cursorModel) []uint {
+		ids := make([]uint, 0)
+		for _, model := range models {
+			ids = append(ids, model.ID)
+		}
+		return ids
+	}
+
+	for _, orders := range [][]string{
+		{"ASC", "ASC"},
+		{"DESC", "DESC"},
+		{"ASC NULLS LAST", "ASC NULLS LAST"},
+		{"DESC NULLS FIRST", "DESC NULLS FIRST"},
+	} {
+		var all []cursorModel
+		cursor := (&pageboy.Cursor{Limit: 100}).Paginate("SubID", "ID").Order(orders...)
+		assertNoError(t, db.Scopes(cursor.Scope()).Find(&all).Error)
+		assertEqual(t, len(all), 6)
+
+		isAsc := orders[0][:3] == "ASC"
+		for i, model := range all {
+			position := pageboy.CursorOf(model, "SubID", "ID")
+
+			var since, until []cursorModel
+			cursor := (&pageboy.Cursor{Limit: 100, Since: position}).Paginate("SubID", "ID").Order(orders...)
+			assertNoError(t, db.Scopes(cursor.Scope()).Find(&since).Error)
+			cursor = (&pageboy.Cursor{Limit: 100, Until: position}).Paginate("SubID", "ID").Order(orders...)
+			assertNoError(t, db.Scopes(cursor.Scope()).Find(&until).Error)
+
+			if isAsc {
+				assertEqual(t, ids(since), ids(all[i:]))
+				assertEqual(t, ids(until), ids(all[:i+1]))
+			} else {
+				assertEqual(t, ids(since), ids(all[:i+1]))
+				assertEqual(t, ids(until), ids(all[i:]))
+			}
+		}
+	}
+
+	// re-fetch the current page.
+	var page, again []cursorModel
+	cursor := (&pageboy.Cursor{Limit: 2}).Paginate("SubID", "ID").Order("ASC", "ASC")
+	assertNoError(t, db.Scopes(cursor.Scope()).Find(&page).Error)
+	cursor = (&pageboy.Cursor{Limit: 2, Since: pageboy.CursorOf(page[0], "SubID", "ID")}).Paginate("SubID", "ID").Order("ASC", "ASC")
+	assertNoError(t, db.Scopes(cursor.Scope()).Find(&again).Error)
+	assertEqual(t, ids(again), ids(page))
+
+	u, _ := url.Parse("https://example.com/models?since=1")
+	assertEqual(t, cursor.BuildNextPagingUrls(u).Next, "https://example.com/models?after="+url.QueryEscape(string(cursor.GetNextAfter())))
+
+	assertError(t, (&pageboy.Cursor{Limit: 1, After: "1", Since: "1"}).Validate())
+	assertError(t, (&pageboy.Cursor{Limit: 1, Before: "1", Until: "1"}).Validate())
+	assertNoError(t, (&pageboy.Cursor{Limit: 1, After: "1", Until: "3"}).Validate())
+}
+
+func TestCursorFill(t *testing.T) {
+	db := openDB()
+	assertNoError(t, db.Migrator().DropTable(&cursorModel{}))
+	assertNoError(t, db.AutoMigrate(&cursorModel{}))
+
+	for i := 0; i < 10; i++ {
+		assertNoError(t, db.Create(&cursorModel{}).Error)
+	}
+
+	ids := func(models []cursorModel) []uint {
+		ids := make([]uint, 0)
+		for _, model := range models {
+			ids = append(ids, model.ID)
+		}
+		return ids
+	}
+
+	var models []cursorModel
+	cursor := (&pageboy.Cursor{After: "2", Before: "9", Limit: 3}).Paginate("ID").Order(ASC)
+	assertNoError(t, db.Scopes(cursor.Scope()).Find(&models).Error)
+	assertEqual(t, ids(models), []uint{3, 4, 5})
+
+	cursor.Fill(pageboy.FillFromBefore)
+	result := &pageboy.CursorResult{}
+	assertNoError(t, db.Scopes(cursor.ScopeWithResult(result)).Find(&models).Error)
+	assertEqual(t, ids(models), []uint{6, 7, 8})
+	assertEqual(t, result.HasMore(), true)
+	assertEqual(t, result.GapClosed(), false)
+	assertEqual(t, result.GetNextBefore(), pbc.CursorString("6"))
+	assertEqual(t, result.GetNextAfter(), pbc.CursorString("8"))
+	u, _ := url.Parse("https://example.com/models?after=2&before=9")
+	assertEqual(t, result.BuildNextPagingUrls(u).Next, "https://example.com/models?after=2&before=6")
+
+	cursor.Limit = 10
+	assertNoError(t, db.Scopes(cursor.ScopeWithResult(result)).Find(&models).Error)
+	assertEqual(t, ids(models), []uint{3, 4, 5, 6, 7, 8})
+	assertEqual(t, result.GapClosed(), true)
+
+	cursor = (&pageboy.Cursor{After: "2", Before: "9", Limit: 3}).Paginate("ID").Order(DESC).Fill(pageboy.FillFromAfter)
+	assertNoError(t, db.Scopes(cursor.ScopeWithResult(result)).Find(&models).Error)
+	assertEqual(t, ids(models), []uint{5, 4, 3})
+	assertEqual(t, result.HasMore(), true)
+
+	cursor = (&pageboy.Cursor{After: "2", Before: "9", Limit: 3}).Paginate("ID").Order(DESC).Fill(pageboy.FillFromBefore)
+	assertNoError(t, db.Scopes(cursor.ScopeWithResult(result)).Find(&models).Error)
+	assertEqual(t, ids(models), []uint{8, 7, 6})
+
+	// closes the gap from the end of Before.
+	var batches [][]uint
+	cursor = (&pageboy.Cursor{After: "2", Before: "9", Limit: 2}).Paginate("ID").Order(ASC).Fill(pageboy.FillFromBefore)
+	assertNoError(t, pageboy.Each(context.Background(), db, cursor, func(batch []cursorModel) error {
+		batches = append(batches, ids(batch))
+		return nil
+	}))
+	assertEqual(t, batches, [][]uint{{7, 8}, {5, 6}, {3, 4}})
+}
+
+func TestCursorCounts(t *testing.T) {
+	db := openDB()
+	assertNoError(t, db.Migrator().DropTable(&cursorModel{}))
+	assertNoError(t, db.AutoMigrate(&cursorModel{}))
+
+	for i := 0; i < 10; i++ {
+		assertNoError(t, db.Create(&cursorModel{}).Error)
+	}
+
+	var models []cursorModel
+	cursor := (&pageboy.Cursor{After: "5", Limit: 2}).Paginate("ID").Order(ASC)
+	assertNoError(t, db.Where("id > ?", 1).Scopes(cursor.Scope()).Find(&models).Error)
+	assertEqual(t, cursor.Summary().Total, (*pageboy.CountSummary)(nil))
+	assertEqual(t, cursor.Summary().Remaining, (*pageboy.CountSummary)(nil))
+
+	cursor.Counts(pageboy.Counts{Total: pageboy.CountExact, Remaining: pageboy.CountExact})
+	assertNoError(t, db.Where("id > ?", 1).Scopes(cursor.Scope()).Find(&models).Error)
+	assertEqual(t, len(models), 2)
+	assertEqual(t, cursor.Summary().Total, &pageboy.CountSummary{Count: 9})
+	assertEqual(t, cursor.Summary().Remaining, &pageboy.CountSummary{Count: 5})
+
+	cursor.Counts(pageboy.Counts{Total: pageboy.CountCapped, Remaining: pageboy.CountCapped, Cap: 5})
+	assertNoError(t, db.Where("id > ?", 1).Scopes(cursor.Scope()).Find(&models).Error)
+	assertEqual(t, cursor.Summary().Total, &pageboy.CountSummary{Count: 5, IsCapped: true})
+	assertEqual(t, cursor.Summary().Remaining, &pageboy.CountSummary{Count: 5})
+	b, err := json.Marshal(cursor.Summary())
+	assertNoError(t, err)
+	assertEqual(t, string(b), `{"limit":2,"next_before":"6","next_after":"7","has_more":true,"reverse":false,"total":{"count":5,"is_capped":true},"remaining":{"count":5}}`)
+
+	cursor.Counts(pageboy.Counts{Total: pageboy.CountEstimated})
+	result := &pageboy.CursorResult{}
+	assertNoError(t, db.Scopes(cursor.ScopeWithResult(result)).Find(&models).Error)
+	assertEqual(t, result.Summary().Total.Count > 0, true)
+	assertEqual(t, result.Summary().Remaining, (*pageboy.CountSummary)(nil))
+	if db.Dialector.Name() != "postgres" {
+		assertEqual(t, result.Summary().Total, &pageboy.CountSummary{Count: 10})
+	}
+}
+
+func TestCursorSummary(t *testing.T) {
+	db := openDB()
+	assertNoError(t, db.Migrator().DropTable(&cursorModel{}))
+	assertNoError(t, db.AutoMigrate(&cursorModel{}))
+
+	for i := 0; i < 5; i++ {
+		assertNoError(t, db.Create(&cursorModel{}).Error)
+	}
+
+	base, _ := url.Parse("https://example.com/models?limit=2")
+
+	var models []cursorModel
+	cursor := (&pageboy.Cursor{Limit: 2}).Paginate("ID").Order(ASC)
+	assertNoError(t, db.Scopes(cursor.Scope()).Find(&models).Error)
+	assertEqual(t, cursor.Summary(), &pageboy.CursorSummary{Limit: 2, NextBefore: "1", NextAfter: "2", HasMore: true})
+	assertEqual(t, cursor.SummaryWithURLs(base), &pageboy.CursorSummary{
+		Limit:      2,
+		NextBefore: "1",
+		NextAfter:  "2",
+		HasMore:    true,
+		NextURL:    cursor.BuildNextPagingUrls(base).Next,
+	})
+	assertEqual(t, cursor.SummaryWithURLs(base).NextURL, "https://example.com/models?after=2&limit=2")
+
+	cursor.After = "2"
+	assertNoError(t, db.Scopes(cursor.Scope()).Find(&models).Error)
+	summary := cursor.SummaryWithURLs(base)
+	assertEqual(t, summary.NextURL, "https://example.com/models?after=4&limit=2")
+	assertEqual(t, summary.PrevURL, "https://example.com/models?before=3&limit=2&reverse=true")
+
+	// the records before the current records.
+	prevURL, _ := url.Parse(summary.PrevURL)
+	prev, err := pageboy.CursorFromRequest(&http.Request{URL: prevURL})
+	assertNoError(t, err)
+	assertNoError(t, db.Scopes(prev.Paginate("ID").Order(ASC).Scope()).Find(&models).Error)
+	assertEqual(t, []uint{models[0].ID, models[1].ID}, []uint{2, 1})
+
+	b, err := json.Marshal(prev.SummaryWithURLs(prevURL))
+	assertNoError(t, err)
+	assertEqual(t, string(b), `{"limit":2,"next_before":"1","next_after":"2","has_more":false,"reverse":true,"prev_url":"https://example.com/models?after=2\u0026limit=2"}`)
+}
+
+func TestCursorMapAndScalarDestinations(t *testing.T) {
+	db := openDB()
+	assertNoError(t, db.Migrator().DropTable(&cursorModel{}))
+	assertNoError(t, db.AutoMigrate(&cursorModel{}))
+
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		createdAt := now.Add(time.Duration(i) * time.Second)
+		assertNoError(t, db.Create(&cursorModel{Model: gorm.Model{CreatedAt: createdAt}}).Error)
+	}
+
+	// map without model
+	var rows []map[string]interface{}
+	cursor := (&pageboy.Cursor{Limit: 2}).Paginate("ID").Order(ASC)
+	assertNoError(t, db.Table("cursor_models").Scopes(cursor.Scope()).Find(&rows).Error)
+	assertEqual(t, len(rows), 2)
+	assertEqual(t, cursor.GetNextAfter(), pbc.CursorString("2"))
+	assertEqual(t, cursor.Summary().HasMore, true)
+
+	cursor.After = cursor.GetNextAfter()
+	rows = nil // NOTE: the maps are appended to the slice.
+	assertNoError(t, db.Table("cursor_models").Scopes(cursor.Scope()).Find(&rows).Error)
+	assertEqual(t, len(rows), 2)
+	assertEqual(t, cursor.GetNextBefore(), pbc.CursorString("3"))
+	assertEqual(t, cursor.GetNextAfter(), pbc.CursorString("4"))
+
+	// map without model cannot decode the time.
+	var models []cursorModel
+	assertNoError(t, db.Order("id").Find(&models).Error)
+
+	cursor = (&pageboy.Cursor{Limit: 2}).Paginate("CreatedAt", "ID").Order(ASC, ASC)
+	rows = nil
+	err := db.Table("cursor_models").Scopes(cursor.Scope()).Find(&rows).Error
+	assertEqual(t, errors.Is(err, pageboy.ErrModelRequired), true)
+
+	cursor.After = pageboy.CursorOf(models[1], "CreatedAt", "ID")
+	rows = nil
+	err = db.Table("cursor_models").Scopes(cursor.Scope()).Find(&rows).Error
+	assertEqual(t, errors.Is(err, pageboy.ErrModelRequired), true)
+
+	// map with model
+
+	cursor = (&pageboy.Cursor{Limit: 2}).Paginate("CreatedAt", "ID").Order(DESC, DESC)
+	rows = nil
+	assertNoError(t, db.Model(&cursorModel{}).Scopes(cursor.Scope()).Find(&rows).Error)
+	assertEqual(t, len(rows), 2)
+	assertEqual(t, cursor.GetNextBefore(), pageboy.CursorOf(models[3], "CreatedAt", "ID"))
+
+	cursor.Before = cursor.GetNextBefore()
+	rows = nil
+	assertNoError(t, db.Model(&cursorModel{}).Scopes(cursor.Scope()).Find(&rows).Error)
+	assertEqual(t, len(rows), 2)
+	assertEqual(t, cursor.GetNextBefore(), pageboy.CursorOf(models[1], "CreatedAt", "ID"))
+
+	// pluck
+	var ids []uint
+	cursor = (&pageboy.Cursor{Limit: 2, After: "1"}).Paginate("ID").Order(ASC)
+	assertNoError(t, db.Model(&cursorModel{}).Scopes(cursor.Scope()).Pluck("id", &ids).Error)
+	assertEqual(t, ids, []uint{2, 3})
+	assertEqual(t, cursor.GetNextAfter(), pbc.CursorString("3"))
+
+	var times []time.Time
+	cursor = (&pageboy.Cursor{Limit: 2}).Paginate("CreatedAt").Order(ASC)
+	assertNoError(t, db.Table("cursor_models").Scopes(cursor.Scope()).Pluck("created_at", &times).Error)
+	assertEqual(t, len(times), 2)
+	assertEqual(t, cursor.GetNextAfter(), pageboy.CursorOf(models[1], "CreatedAt"))
+
+	cursor.After = cursor.GetNextAfter()
+	assertNoError(t, db.Table("cursor_models").Scopes(cursor.Scope()).Pluck("created_at", &times).Error)
+	assertEqual(t, len(times), 2)
+	assertEqual(t, cursor.GetNextAfter(), pageboy.CursorOf(models[3], "CreatedAt"))
+}
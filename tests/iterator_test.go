@@ -0,0 +1,113 @@
+package pageboy_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/soranoba/pageboy/v4"
+)
+
+func TestIterator(t *testing.T) {
+	db := openDB()
+	assertNoError(t, db.Migrator().DropTable(&cursorModel{}))
+	assertNoError(t, db.AutoMigrate(&cursorModel{}))
+
+	now := time.Now()
+	for _, ti := range []*time.Time{
+		nil,
+		&now,
+		nil,
+		timePtr(now.Add(-10 * time.Second)),
+		&now,
+		timePtr(now.Add(10 * time.Hour)),
+		nil,
+	} {
+		assertNoError(t, db.Create(&cursorModel{Time: ti}).Error)
+	}
+
+	for _, order := range []string{ASC, DESC} {
+		var expected []uint
+		assertNoError(
+			t,
+			db.Model(&cursorModel{}).Order(clauseOrder("time", order)+", id "+order).Pluck("id", &expected).Error,
+		)
+
+		var ids []uint
+		it := pageboy.NewIterator[*cursorModel](db, (&pageboy.Cursor{Limit: 3}).Paginate("Time", "ID").Order(order, order))
+		for it.Next(context.Background()) {
+			assertEqual(t, len(it.Batch()) <= 3, true)
+			for _, model := range it.Batch() {
+				ids = append(ids, model.ID)
+			}
+		}
+		assertNoError(t, it.Err())
+		assertEqual(t, ids, expected)
+
+		ids = nil
+		err := pageboy.Each(context.Background(), db, (&pageboy.Cursor{Limit: 2}).Paginate("Time", "ID").Order(order, order), func(batch []cursorModel) error {
+			for _, model := range batch {
+				ids = append(ids, model.ID)
+			}
+			return nil
+		})
+		assertNoError(t, err)
+		assertEqual(t, ids, expected)
+	}
+}
+
+func TestIterator_error(t *testing.T) {
+	db := openDB()
+	assertNoError(t, db.Migrator().DropTable(&cursorModel{}))
+	assertNoError(t, db.AutoMigrate(&cursorModel{}))
+
+	for i := 0; i < 5; i++ {
+		assertNoError(t, db.Create(&cursorModel{}).Error)
+	}
+
+	cursor := (&pageboy.Cursor{Limit: 2}).Paginate("ID").Order(ASC)
+
+	// stops when the callback returns an error.
+	errStop := errors.New("stop")
+	var count int
+	err := pageboy.Each(context.Background(), db, cursor, func(batch []cursorModel) error {
+		count++
+		return errStop
+	})
+	assertEqual(t, err, errStop)
+	assertEqual(t, count, 1)
+
+	// stops when the context is canceled.
+	ctx, cancel := context.WithCancel(context.Background())
+	count = 0
+	err = pageboy.Each(ctx, db, cursor, func(batch []cursorModel) error {
+		count++
+		cancel()
+		return nil
+	})
+	assertEqual(t, errors.Is(err, context.Canceled), true)
+	assertEqual(t, count, 1)
+
+	// invalid cursor
+	it := pageboy.NewIterator[cursorModel](db, (&pageboy.Cursor{}).Paginate("ID").Order(ASC))
+	assertEqual(t, it.Next(context.Background()), false)
+	assertError(t, it.Err())
+
+	// the query error
+	it = pageboy.NewIterator[cursorModel](db.Where("unknown_column = ?", 1), cursor)
+	assertEqual(t, it.Next(context.Background()), false)
+	assertError(t, it.Err())
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+// clauseOrder returns an ORDER BY expression that sorts NULL as the lowest value on all engines.
+func clauseOrder(column string, order string) string {
+	if order == ASC {
+		return "CASE WHEN " + column + " IS NULL THEN 0 ELSE 1 END, " + column + " ASC"
+	}
+	return "CASE WHEN " + column + " IS NULL THEN 0 ELSE 1 END DESC, " + column + " DESC"
+}
@@ -0,0 +1,83 @@
+package pageboy_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/soranoba/pageboy/v4"
+)
+
+func TestJSONAPICursor(t *testing.T) {
+	db := openDB()
+	assertNoError(t, db.Migrator().DropTable(&cursorModel{}))
+	assertNoError(t, db.AutoMigrate(&cursorModel{}))
+
+	for i := 0; i < 5; i++ {
+		assertNoError(t, db.Create(&cursorModel{}).Error)
+	}
+
+	base, _ := url.Parse("https://example.com/models?filter=a&page%5Bsize%5D=2&page%5Bafter%5D=1")
+
+	cursor := pageboy.NewCursor()
+	assertNoError(t, pageboy.BindJSONAPICursor(base.Query(), cursor))
+	assertEqual(t, cursor.Limit, 2)
+	assertEqual(t, string(cursor.After), "1")
+	assertEqual(t, string(cursor.Before), "")
+
+	var models []cursorModel
+	assertNoError(t, db.Scopes(cursor.Paginate("ID").Order(ASC).Scope()).Find(&models).Error)
+	assertEqual(t, len(models), 2)
+	assertEqual(t, cursor.BuildJSONAPILinks(base), &pageboy.JSONAPILinks{
+		First: "https://example.com/models?filter=a&page%5Bsize%5D=2",
+		Prev:  "https://example.com/models?filter=a&page%5Bbefore%5D=2&page%5Breverse%5D=true&page%5Bsize%5D=2",
+		Next:  "https://example.com/models?filter=a&page%5Bafter%5D=3&page%5Bsize%5D=2",
+	})
+
+	// the prev link reads the records before the current records.
+	prevURL, _ := url.Parse(cursor.BuildJSONAPILinks(base).Prev)
+	prev := pageboy.NewCursor()
+	assertNoError(t, pageboy.BindJSONAPICursor(prevURL.Query(), prev))
+	assertNoError(t, db.Scopes(prev.Paginate("ID").Order(ASC).Scope()).Find(&models).Error)
+	assertEqual(t, len(models), 1)
+	assertEqual(t, models[0].ID, uint(1))
+
+	cursor.After = "3"
+	assertNoError(t, db.Scopes(cursor.Scope()).Find(&models).Error)
+	assertEqual(t, cursor.BuildJSONAPILinks(base).Next, "")
+
+	assertError(t, pageboy.BindJSONAPICursor(url.Values{"page[size]": {"a"}}, cursor))
+}
+
+func TestJSONAPIPager(t *testing.T) {
+	db := openDB()
+	assertNoError(t, db.Migrator().DropTable(&pagerModel{}))
+	assertNoError(t, db.AutoMigrate(&pagerModel{}))
+
+	for i := 0; i < 5; i++ {
+		assertNoError(t, db.Create(&pagerModel{}).Error)
+	}
+
+	base, _ := url.Parse("https://example.com/models?page%5Bnumber%5D=2&page%5Bsize%5D=2")
+
+	pager := pageboy.NewPager()
+	assertNoError(t, pageboy.BindJSONAPIPager(base.Query(), pager))
+	assertEqual(t, pager.Page, 2)
+	assertEqual(t, pager.PerPage, 2)
+
+	var models []pagerModel
+	assertNoError(t, db.Scopes(pager.Scope()).Find(&models).Error)
+	assertEqual(t, pager.BuildJSONAPILinks(base), &pageboy.JSONAPILinks{
+		First: "https://example.com/models?page%5Bnumber%5D=1&page%5Bsize%5D=2",
+		Prev:  "https://example.com/models?page%5Bnumber%5D=1&page%5Bsize%5D=2",
+		Next:  "https://example.com/models?page%5Bnumber%5D=3&page%5Bsize%5D=2",
+		Last:  "https://example.com/models?page%5Bnumber%5D=3&page%5Bsize%5D=2",
+	})
+
+	pager.Page = 3
+	assertNoError(t, db.Scopes(pager.Scope()).Find(&models).Error)
+	links := pager.BuildJSONAPILinks(base)
+	assertEqual(t, links.Next, "")
+	assertEqual(t, links.Prev, "https://example.com/models?page%5Bnumber%5D=2&page%5Bsize%5D=2")
+
+	assertError(t, pageboy.BindJSONAPIPager(url.Values{"page[number]": {"a"}}, pager))
+}
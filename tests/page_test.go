@@ -0,0 +1,86 @@
+package pageboy_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/soranoba/pageboy/v4"
+	pbc "github.com/soranoba/pageboy/v4/core"
+)
+
+func TestFindCursorPage(t *testing.T) {
+	db := openDB()
+	assertNoError(t, db.Migrator().DropTable(&cursorModel{}))
+	assertNoError(t, db.AutoMigrate(&cursorModel{}))
+
+	for i := 0; i < 3; i++ {
+		assertNoError(t, db.Create(&cursorModel{}).Error)
+	}
+
+	cursor := (&pageboy.Cursor{Limit: 2}).Paginate("ID").Order(DESC)
+	page, err := pageboy.FindCursorPage[*cursorModel](db, cursor)
+	assertNoError(t, err)
+	assertEqual(t, len(page.Items), 2)
+	assertEqual(t, page.Items[0].ID, uint(3))
+	assertEqual(t, page.Items[1].ID, uint(2))
+	assertEqual(t, page.NextAfter, pbc.FormatCursorString(uint(3)))
+	assertEqual(t, page.NextBefore, pbc.FormatCursorString(uint(2)))
+	assertEqual(t, page.HasMore, true)
+	assertEqual(t, cursor.GetNextBefore(), pbc.CursorString(""))
+
+	cursor = (&pageboy.Cursor{Before: page.NextBefore, Limit: 2}).Paginate("ID").Order(DESC)
+	page, err = pageboy.FindCursorPage[*cursorModel](db, cursor)
+	assertNoError(t, err)
+	assertEqual(t, len(page.Items), 1)
+	assertEqual(t, page.Items[0].ID, uint(1))
+	assertEqual(t, page.HasMore, false)
+
+	cursor = (&pageboy.Cursor{Before: page.NextBefore, Limit: 2}).Paginate("ID").Order(DESC)
+	page, err = pageboy.FindCursorPage[*cursorModel](db, cursor)
+	assertNoError(t, err)
+	j, err := json.Marshal(page)
+	assertNoError(t, err)
+	assertEqual(t, string(j), `{"items":[],"limit":2,"next_before":"1","next_after":"0","has_more":false,"reverse":false}`)
+
+	// the counts are returned with the records.
+	cursor = (&pageboy.Cursor{Limit: 2}).Paginate("ID").Order(DESC).Counts(pageboy.Counts{Total: pageboy.CountExact, Remaining: pageboy.CountExact})
+	page, err = pageboy.FindCursorPage[*cursorModel](db.Where("id < ?", 3), cursor)
+	assertNoError(t, err)
+	assertEqual(t, len(page.Items), 2)
+	assertEqual(t, page.Total, &pageboy.CountSummary{Count: 2})
+	assertEqual(t, page.Remaining, &pageboy.CountSummary{Count: 2})
+	j, err = json.Marshal(page.CursorSummary)
+	assertNoError(t, err)
+	assertEqual(t, string(j), `{"limit":2,"next_before":"1","next_after":"2","has_more":false,"reverse":false,"total":{"count":2},"remaining":{"count":2}}`)
+
+	_, err = pageboy.FindCursorPage[*cursorModel](db.Where("unknown_column = ?", 1), cursor)
+	assertError(t, err)
+}
+
+func TestFindPagerPage(t *testing.T) {
+	db := openDB()
+	assertNoError(t, db.Migrator().DropTable(&pagerModel{}))
+	assertNoError(t, db.AutoMigrate(&pagerModel{}))
+
+	for _, name := range []string{"Alice", "Bob", "Carol"} {
+		assertNoError(t, db.Create(&pagerModel{Name: name}).Error)
+	}
+
+	type user struct {
+		ID   uint   `json:"id"`
+		Name string `json:"name"`
+	}
+
+	pager := &pageboy.Pager{Page: 2, PerPage: 2}
+	page, err := pageboy.FindPagerPage[user](db.Model(&pagerModel{}).Order("id ASC"), pager)
+	assertNoError(t, err)
+	assertEqual(t, page.Items, []user{{ID: 3, Name: "Carol"}})
+	assertEqual(t, page.PagerSummary, pageboy.PagerSummary{Page: 2, PerPage: 2, TotalCount: 3, TotalPage: 2})
+
+	j, err := json.Marshal(page)
+	assertNoError(t, err)
+	assertEqual(t, string(j), `{"items":[{"id":3,"name":"Carol"}],"page":2,"per_page":2,"total_count":3,"total_page":2}`)
+
+	_, err = pageboy.FindPagerPage[user](db.Model(&pagerModel{}).Where("unknown_column = ?", 1), pager)
+	assertError(t, err)
+}
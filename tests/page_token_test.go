@@ -0,0 +1,96 @@
+package pageboy_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/soranoba/pageboy/v4"
+)
+
+func TestFindTokenPage(t *testing.T) {
+	db := openDB()
+	assertNoError(t, db.Migrator().DropTable(&cursorModel{}))
+	assertNoError(t, db.AutoMigrate(&cursorModel{}))
+
+	for i := 0; i < 5; i++ {
+		assertNoError(t, db.Create(&cursorModel{}).Error)
+	}
+
+	cursor := (&pageboy.Cursor{Limit: 2}).
+		Paginate("ID").
+		Order(DESC).
+		Limits(pageboy.Limits{MaxLimit: 3, Policy: pageboy.ClampOverLimit})
+	type filter struct {
+		MinID uint `json:"min_id"`
+	}
+
+	var ids []uint
+	req := pageboy.PageTokenRequest{Params: filter{MinID: 1}}
+	for i := 0; ; i++ {
+		page, err := pageboy.FindTokenPage[*cursorModel](db.Where("id >= ?", 1), cursor, req)
+		assertNoError(t, err)
+		for _, model := range page.Items {
+			ids = append(ids, model.ID)
+		}
+		if page.NextPageToken == "" {
+			break
+		}
+		req.PageToken = page.NextPageToken
+		if i == 0 {
+			// the page size can be changed, and it is clamped by MaxLimit.
+			req.PageSize = 10
+		}
+	}
+	assertEqual(t, ids, []uint{5, 4, 3, 2, 1})
+
+	page, err := pageboy.FindTokenPage[*cursorModel](db, cursor, pageboy.PageTokenRequest{PageSize: 5})
+	assertNoError(t, err)
+	assertEqual(t, len(page.Items), 3)
+
+	// the token does not match the other parameters.
+	page, err = pageboy.FindTokenPage[*cursorModel](db, cursor, pageboy.PageTokenRequest{Params: filter{MinID: 1}})
+	assertNoError(t, err)
+	_, err = pageboy.FindTokenPage[*cursorModel](db, cursor, pageboy.PageTokenRequest{PageToken: page.NextPageToken, Params: filter{MinID: 2}})
+	var validationErr *pageboy.ValidationError
+	assertEqual(t, errors.As(err, &validationErr), true)
+	assertEqual(t, validationErr.Field, "PageToken")
+
+	_, err = pageboy.FindTokenPage[*cursorModel](db, cursor, pageboy.PageTokenRequest{PageToken: "invalid"})
+	assertEqual(t, errors.As(err, &validationErr), true)
+	assertEqual(t, validationErr.Field, "PageToken")
+
+	// the position of the token is forged.
+	forge := func(cursor *pageboy.Cursor, position string) string {
+		page, err := pageboy.FindTokenPage[*cursorModel](db, cursor, pageboy.PageTokenRequest{PageSize: 1})
+		assertNoError(t, err)
+		b, err := base64.RawURLEncoding.DecodeString(page.NextPageToken)
+		assertNoError(t, err)
+		var token map[string]string
+		assertNoError(t, json.Unmarshal(b, &token))
+		token["p"] = position
+		b, err = json.Marshal(token)
+		assertNoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	_, err = pageboy.FindTokenPage[*cursorModel](db, cursor, pageboy.PageTokenRequest{PageToken: forge(cursor, "1_2")})
+	assertEqual(t, errors.As(err, &validationErr), true)
+	assertEqual(t, validationErr.Field, "PageToken")
+	assertEqual(t, validationErr.Message, "is invalid")
+
+	// the tie-breaker accepts the positions made before and after appending the primary key.
+	appended := pageboy.NewCursor().Paginate("Time").Order(DESC).TieBreaker(pageboy.TieBreakerAppend)
+	_, err = pageboy.FindTokenPage[*cursorModel](db, appended, pageboy.PageTokenRequest{PageToken: forge(appended, "1_2")})
+	assertNoError(t, err)
+	_, err = pageboy.FindTokenPage[*cursorModel](db, appended, pageboy.PageTokenRequest{PageToken: forge(appended, "1_2_3")})
+	assertEqual(t, errors.Is(err, pageboy.ErrInvalidPageToken), true)
+
+	_, err = pageboy.FindTokenPage[*cursorModel](db, cursor, pageboy.PageTokenRequest{PageSize: -1})
+	assertEqual(t, errors.As(err, &validationErr), true)
+	assertEqual(t, validationErr.Field, "PageSize")
+
+	_, err = pageboy.FindTokenPage[*cursorModel](db, cursor.Limits(pageboy.Limits{MaxLimit: 3}), pageboy.PageTokenRequest{PageSize: 4})
+	assertEqual(t, errors.As(err, &validationErr), true)
+	assertEqual(t, validationErr.Field, "PageSize")
+}
@@ -88,6 +88,20 @@ func openDB() *gorm.DB {
 	return db
 }
 
+// limit returns a scope that overwrites the limit.
+func limit(limit int) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Limit(limit)
+	}
+}
+
+// offset returns a scope that overwrites the offset.
+func offset(offset int) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Offset(offset)
+	}
+}
+
 func assertEqual(t *testing.T, got, expected interface{}) bool {
 	if !reflect.DeepEqual(got, expected) {
 		_, file, line, _ := runtime.Caller(1)
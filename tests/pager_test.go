@@ -1,14 +1,19 @@
 package pageboy_test
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/soranoba/pageboy/v4"
+	pbc "github.com/soranoba/pageboy/v4/core"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type pagerModel struct {
@@ -23,10 +28,58 @@ func TestPagerValidate(t *testing.T) {
 	pager = pageboy.Pager{Page: 1, PerPage: 0}
 	assertError(t, pager.Validate())
 
+	pager = pageboy.Pager{Page: -1, PerPage: 1}
+	assertError(t, pager.Validate())
+
+	pager = pageboy.Pager{Page: 1, PerPage: -1}
+	assertError(t, pager.Validate())
+
 	pager = pageboy.Pager{Page: 1, PerPage: 1}
 	assertNoError(t, pager.Validate())
 }
 
+func TestPagerValidateWithLimits(t *testing.T) {
+	limits := pageboy.Limits{MaxPerPage: 50, MaxPage: 100, MaxOffset: 1000}
+
+	pager := &pageboy.Pager{Page: 1, PerPage: 50}
+	assertNoError(t, pager.Limits(limits).Validate())
+
+	pager = &pageboy.Pager{Page: 1, PerPage: 51}
+	assertError(t, pager.Limits(limits).Validate())
+
+	pager = &pageboy.Pager{Page: 101, PerPage: 1}
+	assertError(t, pager.Limits(limits).Validate())
+
+	pager = &pageboy.Pager{Page: 22, PerPage: 50}
+	assertError(t, pager.Limits(limits).Validate())
+
+	limits.Policy = pageboy.ClampOverLimit
+
+	// the clamped values are applied to the query, and the pager is not changed.
+	db := openDB()
+	assertNoError(t, db.Migrator().DropTable(&pagerModel{}))
+	assertNoError(t, db.AutoMigrate(&pagerModel{}))
+
+	var models []*pagerModel
+	pager = &pageboy.Pager{Page: 1, PerPage: 100000}
+	assertNoError(t, pager.Limits(limits).Validate())
+	assertNoError(t, db.Scopes(pager.Scope()).Find(&models).Error)
+	assertEqual(t, pager.PerPage, 100000)
+	assertEqual(t, pager.Summary().PerPage, 50)
+
+	pager = &pageboy.Pager{Page: 500000, PerPage: 1}
+	assertNoError(t, pager.Limits(limits).Validate())
+	assertNoError(t, db.Scopes(pager.Scope()).Find(&models).Error)
+	assertEqual(t, pager.Page, 500000)
+	assertEqual(t, pager.Summary().Page, 100)
+
+	pager = &pageboy.Pager{Page: 500000, PerPage: 50}
+	assertNoError(t, pager.Limits(limits).Validate())
+	assertNoError(t, db.Scopes(pager.Scope()).Find(&models).Error)
+	assertEqual(t, pager.Page, 500000)
+	assertEqual(t, pager.Summary().Page, 21)
+}
+
 func TestPagerPaginate(t *testing.T) {
 	db := openDB().Debug()
 	assertNoError(t, db.Migrator().DropTable(&pagerModel{}))
@@ -130,6 +183,164 @@ func TestPagerPaginateWithWhere(t *testing.T) {
 	assertEqual(t, *pager.Summary(), pageboy.PagerSummary{Page: 1, PerPage: 2, TotalCount: 2, TotalPage: 1})
 }
 
+func TestPagerPaginateWithAnchor(t *testing.T) {
+	db := openDB()
+	assertNoError(t, db.Migrator().DropTable(&pagerModel{}))
+	assertNoError(t, db.AutoMigrate(&pagerModel{}))
+
+	now := time.Now()
+
+	create := func(createdAt time.Time) *pagerModel {
+		model := &pagerModel{
+			Model: gorm.Model{
+				CreatedAt: createdAt,
+			},
+		}
+		assertNoError(t, db.Create(model).Error)
+		return model
+	}
+
+	model1 := create(now)
+	model2 := create(now)
+	model3 := create(now.Add(10 * time.Second))
+	model4 := create(now.Add(10 * time.Hour))
+	model5 := create(now.Add(-10 * time.Hour))
+
+	var models []*pagerModel
+	pager := &pageboy.Pager{Page: 1, PerPage: 2}
+	assertNoError(t, db.Scopes(pager.Paginate("CreatedAt", "ID").Order(DESC, DESC).Scope()).Find(&models).Error)
+	assertEqual(t, len(models), 2)
+	assertEqual(t, models[0].ID, model4.ID)
+	assertEqual(t, models[1].ID, model3.ID)
+	assertEqual(t, *pager.Summary(), pageboy.PagerSummary{
+		Page:       1,
+		PerPage:    2,
+		TotalCount: 5,
+		TotalPage:  3,
+		NextAnchor: pbc.FormatCursorString(1, &models[1].CreatedAt, models[1].ID),
+	})
+
+	// NOTE: the records that is added before the anchor do not shift the next page.
+	create(now.Add(20 * time.Hour))
+
+	pager = &pageboy.Pager{Page: 2, PerPage: 2, Anchor: pager.Summary().NextAnchor}
+	assertNoError(t, pager.Validate())
+	assertNoError(t, db.Scopes(pager.Paginate("CreatedAt", "ID").Order(DESC, DESC).Scope()).Find(&models).Error)
+	assertEqual(t, len(models), 2)
+	assertEqual(t, models[0].ID, model2.ID)
+	assertEqual(t, models[1].ID, model1.ID)
+	assertEqual(t, pager.Summary().NextAnchor, pbc.FormatCursorString(2, &models[1].CreatedAt, models[1].ID))
+
+	// The anchor of the other page is ignored, and it uses OFFSET.
+	pager = &pageboy.Pager{Page: 3, PerPage: 2, Anchor: pager.Anchor}
+	assertNoError(t, db.Scopes(pager.Paginate("CreatedAt", "ID").Order(DESC, DESC).Scope()).Find(&models).Error)
+	assertEqual(t, len(models), 2)
+	assertEqual(t, models[0].ID, model1.ID)
+	assertEqual(t, models[1].ID, model5.ID)
+	assertEqual(t, *pager.Summary(), pageboy.PagerSummary{
+		Page:       3,
+		PerPage:    2,
+		TotalCount: 6,
+		TotalPage:  3,
+		NextAnchor: pbc.FormatCursorString(3, &models[1].CreatedAt, models[1].ID),
+	})
+
+	pager = &pageboy.Pager{Page: 1, PerPage: 2, Anchor: "invalid"}
+	assertError(t, pager.Validate())
+}
+
+func TestPagerPaginateWithLimits(t *testing.T) {
+	db := openDB()
+	assertNoError(t, db.Migrator().DropTable(&pagerModel{}))
+	assertNoError(t, db.AutoMigrate(&pagerModel{}))
+
+	for i := 0; i < 5; i++ {
+		assertNoError(t, db.Create(&pagerModel{}).Error)
+	}
+
+	var models []*pagerModel
+	pager := (&pageboy.Pager{Page: 1, PerPage: 2}).Limits(pageboy.Limits{MaxPerPage: 2})
+	assertError(t, db.Scopes(pager.Scope(), limit(100)).Order("id ASC").Find(&models).Error)
+
+	pager = (&pageboy.Pager{Page: 1, PerPage: 2}).Limits(pageboy.Limits{MaxPerPage: 2, Policy: pageboy.ClampOverLimit})
+	assertNoError(t, db.Scopes(pager.Scope(), limit(100)).Order("id ASC").Find(&models).Error)
+	assertEqual(t, len(models), 2)
+
+	pager = (&pageboy.Pager{Page: 1, PerPage: 2}).Limits(pageboy.Limits{MaxOffset: 2})
+	assertError(t, db.Scopes(pager.Scope(), offset(4)).Order("id ASC").Find(&models).Error)
+
+	pager = (&pageboy.Pager{Page: 1, PerPage: 2}).Limits(pageboy.Limits{MaxPage: 2, Policy: pageboy.ClampOverLimit})
+	assertNoError(t, db.Scopes(pager.Scope(), offset(4)).Order("id ASC").Find(&models).Error)
+	assertEqual(t, len(models), 2)
+	assertEqual(t, models[0].ID, uint(3))
+}
+
+func TestPagerPaginateOutOfRange(t *testing.T) {
+	db := openDB()
+	assertNoError(t, db.Migrator().DropTable(&pagerModel{}))
+	assertNoError(t, db.AutoMigrate(&pagerModel{}))
+
+	for i := 0; i < 5; i++ {
+		assertNoError(t, db.Create(&pagerModel{}).Error)
+	}
+
+	var models []*pagerModel
+	pager := (&pageboy.Pager{Page: 4, PerPage: 2}).OutOfRange(pageboy.OutOfRangeEmpty)
+	assertNoError(t, db.Scopes(pager.Scope()).Order("id ASC").Find(&models).Error)
+	assertEqual(t, len(models), 0)
+	assertEqual(t, *pager.Summary(), pageboy.PagerSummary{Page: 4, PerPage: 2, TotalCount: 5, TotalPage: 3})
+
+	pager = (&pageboy.Pager{Page: 4, PerPage: 2}).OutOfRange(pageboy.OutOfRangeLastPage)
+	assertNoError(t, db.Scopes(pager.Scope()).Order("id ASC").Find(&models).Error)
+	assertEqual(t, len(models), 1)
+	assertEqual(t, models[0].ID, uint(5))
+	assertEqual(t, *pager.Summary(), pageboy.PagerSummary{Page: 3, PerPage: 2, TotalCount: 5, TotalPage: 3})
+
+	pager = (&pageboy.Pager{Page: 4, PerPage: 2}).OutOfRange(pageboy.OutOfRangeError)
+	err := db.Scopes(pager.Scope()).Order("id ASC").Find(&models).Error
+	assertEqual(t, errors.Is(err, pageboy.ErrPageOutOfRange), true)
+
+	pager = (&pageboy.Pager{Page: 3, PerPage: 2}).OutOfRange(pageboy.OutOfRangeError)
+	assertNoError(t, db.Scopes(pager.Scope()).Order("id ASC").Find(&models).Error)
+	assertEqual(t, len(models), 1)
+
+	// The first page is not out of range even if there are no records.
+	assertNoError(t, db.Where("1 = 1").Delete(&pagerModel{}).Error)
+	pager = (&pageboy.Pager{Page: 1, PerPage: 2}).OutOfRange(pageboy.OutOfRangeError)
+	assertNoError(t, db.Scopes(pager.Scope()).Order("id ASC").Find(&models).Error)
+	assertEqual(t, len(models), 0)
+
+	pager = (&pageboy.Pager{Page: 2, PerPage: 2}).OutOfRange(pageboy.OutOfRangeLastPage)
+	assertNoError(t, db.Scopes(pager.Scope()).Order("id ASC").Find(&models).Error)
+	assertEqual(t, len(models), 0)
+	assertEqual(t, *pager.Summary(), pageboy.PagerSummary{Page: 1, PerPage: 2, TotalCount: 0, TotalPage: 0})
+}
+
+func TestPagerPaginateWithConcurrentCount(t *testing.T) {
+	db := openDB()
+	assertNoError(t, db.Migrator().DropTable(&pagerModel{}))
+	assertNoError(t, db.AutoMigrate(&pagerModel{}))
+
+	for i := 0; i < 5; i++ {
+		assertNoError(t, db.Create(&pagerModel{Name: strconv.Itoa(i % 2)}).Error)
+	}
+
+	var models []*pagerModel
+	pager := (&pageboy.Pager{Page: 2, PerPage: 2}).ConcurrentCount(true)
+	assertNoError(t, db.Scopes(pager.Scope()).Where("name = ?", "0").Order("id ASC").Find(&models).Error)
+	assertEqual(t, len(models), 1)
+	assertEqual(t, models[0].ID, uint(5))
+	assertEqual(t, *pager.Summary(), pageboy.PagerSummary{Page: 2, PerPage: 2, TotalCount: 3, TotalPage: 2})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	pager = (&pageboy.Pager{Page: 1, PerPage: 2}).ConcurrentCount(true)
+	assertError(t, db.WithContext(ctx).Scopes(pager.Scope()).Order("id ASC").Find(&models).Error)
+
+	pager = (&pageboy.Pager{Page: 1, PerPage: 2}).ConcurrentCount(true)
+	assertError(t, db.Scopes(pager.Scope()).Where("unknown_column = ?", 1).Find(&models).Error)
+}
+
 func TestPager_preload(t *testing.T) {
 	db := openDB()
 
@@ -157,6 +368,80 @@ func TestPager_preload(t *testing.T) {
 	assertEqual(t, *pager.Summary(), pageboy.PagerSummary{Page: 1, PerPage: 2, TotalCount: 1, TotalPage: 1})
 }
 
+func TestPager_group_distinct_joins(t *testing.T) {
+	db := openDB()
+
+	assertNoError(t, db.Migrator().DropTable(&User{}))
+	assertNoError(t, db.Migrator().DropTable(&Group{}))
+	assertNoError(t, db.AutoMigrate(&Group{}))
+	assertNoError(t, db.AutoMigrate(&User{}))
+
+	for _, user := range []*User{
+		{Name: "Alice", Groups: []Group{{Name: "A"}, {Name: "B"}, {Name: "C"}}},
+		{Name: "Bob", Groups: []Group{{Name: "A"}}},
+		{Name: "Alice", Groups: []Group{{Name: "B"}, {Name: "C"}}},
+		{Name: "Carol"},
+	} {
+		assertNoError(t, db.Create(user).Error)
+	}
+
+	type groupCount struct {
+		UserID uint
+		Count  int
+	}
+
+	var counts []*groupCount
+	pager := &pageboy.Pager{Page: 1, PerPage: 2}
+	assertNoError(
+		t,
+		db.Model(&Group{}).Select("user_id, COUNT(*) AS count").Group("user_id").
+			Scopes(pager.Scope()).Order("user_id ASC").Find(&counts).Error,
+	)
+	assertEqual(t, len(counts), 2)
+	assertEqual(t, *counts[0], groupCount{UserID: 1, Count: 3})
+	assertEqual(t, *counts[1], groupCount{UserID: 2, Count: 1})
+	assertEqual(t, *pager.Summary(), pageboy.PagerSummary{Page: 1, PerPage: 2, TotalCount: 3, TotalPage: 2})
+
+	var users []*User
+	pager = &pageboy.Pager{Page: 1, PerPage: 2}
+	assertNoError(t, db.Distinct("name").Scopes(pager.Scope()).Order("name ASC").Find(&users).Error)
+	assertEqual(t, len(users), 2)
+	assertEqual(t, *pager.Summary(), pageboy.PagerSummary{Page: 1, PerPage: 2, TotalCount: 3, TotalPage: 2})
+
+	// NOTE: GROUPS is a reserved word in MySQL 8.0, so the table name must be quoted.
+	groupsTable := clause.Table{Name: "groups"}
+	pager = &pageboy.Pager{Page: 1, PerPage: 2}
+	assertNoError(
+		t,
+		db.Distinct("users.id", "users.name").Joins("INNER JOIN ? AS user_groups ON user_groups.user_id = users.id", groupsTable).
+			Where("user_groups.name IN ?", []string{"A", "B"}).
+			Scopes(pager.Scope()).Order("users.id ASC").Find(&users).Error,
+	)
+	assertEqual(t, len(users), 2)
+	assertEqual(t, *pager.Summary(), pageboy.PagerSummary{Page: 1, PerPage: 2, TotalCount: 3, TotalPage: 2})
+
+	// the joined rows are counted, because they are paginated.
+	pager = &pageboy.Pager{Page: 1, PerPage: 2}
+	assertNoError(
+		t,
+		db.Select("*").Joins("INNER JOIN ? AS user_groups ON user_groups.user_id = users.id", groupsTable).
+			Scopes(pager.Scope()).Order("users.id ASC").Find(&users).Error,
+	)
+	assertEqual(t, len(users), 2)
+	assertEqual(t, *pager.Summary(), pageboy.PagerSummary{Page: 1, PerPage: 2, TotalCount: 6, TotalPage: 3})
+
+	// the distinct primary keys are counted with CountDistinct.
+	pager = (&pageboy.Pager{Page: 1, PerPage: 2}).CountDistinct(true)
+	assertNoError(
+		t,
+		db.Select("*").Joins("INNER JOIN ? AS user_groups ON user_groups.user_id = users.id", groupsTable).
+			Where("user_groups.name IN ?", []string{"A", "B"}).
+			Scopes(pager.Scope()).Order("users.id ASC").Find(&users).Error,
+	)
+	assertEqual(t, len(users), 2)
+	assertEqual(t, *pager.Summary(), pageboy.PagerSummary{Page: 1, PerPage: 2, TotalCount: 3, TotalPage: 2})
+}
+
 func TestPager_concurrency(t *testing.T) {
 	db := openDB()
 	assertNoError(t, db.Migrator().DropTable(&cursorModel{}))
@@ -178,12 +463,47 @@ func TestPager_concurrency(t *testing.T) {
 			}
 			var models []cursorModel
 			assertNoError(t, db.Session(&gorm.Session{}).Scopes(pager.Scope()).Find(&models).Error)
+
+			pager = (&pageboy.Pager{Page: 1, PerPage: 10}).ConcurrentCount(true)
+			assertNoError(t, db.Session(&gorm.Session{}).Scopes(pager.Scope()).Find(&models).Error)
+			assertEqual(t, pager.Summary().TotalCount, int64(5))
 			wg.Done()
 		}()
 	}
 	wg.Wait()
 }
 
+func TestPager_shared(t *testing.T) {
+	db := openDB()
+	assertNoError(t, db.Migrator().DropTable(&pagerModel{}))
+	assertNoError(t, db.AutoMigrate(&pagerModel{}))
+
+	for i := 0; i < 5; i++ {
+		assertNoError(t, db.Create(&pagerModel{Name: strconv.Itoa(i % 2)}).Error)
+	}
+
+	pager := (&pageboy.Pager{Page: 1, PerPage: 2}).ConcurrentCount(true)
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			var models []pagerModel
+			result := &pageboy.PagerResult{}
+			name := strconv.Itoa(i % 2)
+			assertNoError(t, db.Session(&gorm.Session{}).Where("name = ?", name).Scopes(pager.ScopeWithResult(result)).Find(&models).Error)
+			assertEqual(t, len(models), 2)
+			assertEqual(t, result.Summary().TotalCount, int64(3-i%2))
+
+			assertNoError(t, db.Session(&gorm.Session{}).Scopes(pager.Scope()).Find(&models).Error)
+			assertEqual(t, pager.Summary().TotalCount, int64(5))
+		}(i)
+	}
+	wg.Wait()
+}
+
 func ExamplePager() {
 	db := openDB()
 
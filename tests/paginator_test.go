@@ -0,0 +1,121 @@
+package pageboy_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/soranoba/pageboy/v4"
+)
+
+func TestDetectPaginator(t *testing.T) {
+	db := openDB()
+	assertNoError(t, db.Migrator().DropTable(&cursorModel{}))
+	assertNoError(t, db.AutoMigrate(&cursorModel{}))
+
+	for i := 0; i < 5; i++ {
+		assertNoError(t, db.Create(&cursorModel{}).Error)
+	}
+
+	cursor := pageboy.NewCursor().Paginate("ID").Order(ASC)
+	pager := pageboy.NewPager().Paginate("ID").Order(ASC)
+
+	// cursor
+	base, _ := url.Parse("https://example.com/models?limit=2&after=1")
+	paginator, err := pageboy.DetectPaginator(base.Query(), cursor, pager)
+	assertNoError(t, err)
+	c, ok := paginator.(*pageboy.Cursor)
+	if assertEqual(t, ok, true) {
+		assertEqual(t, c.Limit, 2)
+		assertEqual(t, string(c.After), "1")
+		assertEqual(t, string(cursor.After), "")
+	}
+
+	var models []cursorModel
+	assertNoError(t, paginator.Validate())
+	assertNoError(t, db.Scopes(paginator.Scope()).Find(&models).Error)
+	assertEqual(t, []uint{models[0].ID, models[1].ID}, []uint{2, 3})
+	assertEqual(t, paginator.Links(base), &pageboy.PagingLinks{
+		First: "https://example.com/models?limit=2",
+		Prev:  "https://example.com/models?before=2&limit=2&reverse=true",
+		Next:  "https://example.com/models?after=3&limit=2",
+	})
+	b, err := json.Marshal(paginator.PaginationSummary())
+	assertNoError(t, err)
+	assertEqual(t, string(b), `{"limit":2,"next_before":"2","next_after":"3","has_more":true,"reverse":false}`)
+
+	// pager
+	base, _ = url.Parse("https://example.com/models?page=2&per_page=2")
+	paginator, err = pageboy.DetectPaginator(base.Query(), cursor, pager)
+	assertNoError(t, err)
+	p, ok := paginator.(*pageboy.Pager)
+	if assertEqual(t, ok, true) {
+		assertEqual(t, p.Page, 2)
+		assertEqual(t, p.PerPage, 2)
+		assertEqual(t, pager.Page, 1)
+	}
+
+	assertNoError(t, db.Scopes(paginator.Scope()).Find(&models).Error)
+	assertEqual(t, []uint{models[0].ID, models[1].ID}, []uint{3, 4})
+	assertEqual(t, paginator.Links(base), &pageboy.PagingLinks{
+		First: "https://example.com/models?page=1&per_page=2",
+		Prev:  "https://example.com/models?page=1&per_page=2",
+		Next:  "https://example.com/models?anchor=2_4&page=3&per_page=2",
+		Last:  "https://example.com/models?anchor=2_4&page=3&per_page=2",
+	})
+	assertEqual(t, paginator.PaginationSummary(), &pageboy.PagerSummary{Page: 2, PerPage: 2, TotalCount: 5, TotalPage: 3, NextAnchor: "2_4"})
+
+	// no parameters
+	paginator, err = pageboy.DetectPaginator(url.Values{}, cursor, pager)
+	assertNoError(t, err)
+	_, ok = paginator.(*pageboy.Cursor)
+	assertEqual(t, ok, true)
+
+	paginator, err = pageboy.DetectPaginator(url.Values{}, nil, pager)
+	assertNoError(t, err)
+	_, ok = paginator.(*pageboy.Pager)
+	assertEqual(t, ok, true)
+
+	// mixed parameters
+	_, err = pageboy.DetectPaginator(url.Values{"after": {"1"}, "page": {"2"}}, cursor, pager)
+	var validationErr *pageboy.ValidationError
+	if assertEqual(t, errors.As(err, &validationErr), true) {
+		assertEqual(t, validationErr.Field, "page")
+		assertEqual(t, validationErr.Message, "cannot be used with after")
+	}
+
+	// not supported
+	_, err = pageboy.DetectPaginator(url.Values{"page": {"2"}}, cursor, nil)
+	assertError(t, err)
+
+	// invalid values
+	_, err = pageboy.DetectPaginator(url.Values{"limit": {"a"}}, cursor, pager)
+	assertError(t, err)
+
+	// the links use the names of the parameters.
+	names := pageboy.ParamNames{Before: "b", After: "a", Limit: "n", Reverse: "r", Page: "p", PerPage: "pp"}
+	base, _ = url.Parse("https://example.com/models?n=2&a=1")
+	paginator, err = names.DetectPaginator(base.Query(), cursor, pager)
+	assertNoError(t, err)
+	assertNoError(t, db.Scopes(paginator.Scope()).Find(&models).Error)
+	assertEqual(t, paginator.Links(base), &pageboy.PagingLinks{
+		First: "https://example.com/models?n=2",
+		Prev:  "https://example.com/models?b=2&n=2&r=true",
+		Next:  "https://example.com/models?a=3&n=2",
+	})
+
+	base, _ = url.Parse("https://example.com/models?p=2&pp=2")
+	paginator, err = names.DetectPaginator(base.Query(), cursor, pager)
+	assertNoError(t, err)
+	assertNoError(t, db.Scopes(paginator.Scope()).Find(&models).Error)
+	assertEqual(t, paginator.Links(base).Prev, "https://example.com/models?p=1&pp=2")
+
+	// page[size] is shared by the cursor and the pager.
+	paginator, err = pageboy.JSONAPIParamNames.DetectPaginator(url.Values{"page[size]": {"2"}, "page[number]": {"2"}}, cursor, pager)
+	assertNoError(t, err)
+	p, ok = paginator.(*pageboy.Pager)
+	if assertEqual(t, ok, true) {
+		assertEqual(t, p.PerPage, 2)
+	}
+}
@@ -0,0 +1,109 @@
+package pageboy_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/soranoba/pageboy/v4"
+)
+
+func TestPartition(t *testing.T) {
+	db := openDB()
+	assertNoError(t, db.Migrator().DropTable(&cursorModel{}))
+	assertNoError(t, db.AutoMigrate(&cursorModel{}))
+
+	now := time.Now()
+	for i := 0; i < 10; i++ {
+		assertNoError(t, db.Create(&cursorModel{Time: timePtr(now.Add(time.Duration(i%3) * time.Hour))}).Error)
+	}
+
+	for _, order := range []string{ASC, DESC} {
+		for _, reverse := range []bool{false, true} {
+			var expected []uint
+			o := order
+			if reverse {
+				o = map[string]string{ASC: DESC, DESC: ASC}[order]
+			}
+			assertNoError(t, db.Model(&cursorModel{}).Order("time "+o+", id "+o).Pluck("id", &expected).Error)
+
+			cursor := (&pageboy.Cursor{Limit: 2, Reverse: reverse}).Paginate("Time", "ID").Order(order, order)
+			cursors, err := pageboy.Partition(db.Model(&cursorModel{}), cursor, 3)
+			assertNoError(t, err)
+			assertEqual(t, len(cursors), 3)
+
+			var ids []uint
+			var sizes []int
+			for _, c := range cursors {
+				var size int
+				assertNoError(t, pageboy.Each(context.Background(), db, c, func(batch []cursorModel) error {
+					for _, model := range batch {
+						ids = append(ids, model.ID)
+					}
+					size += len(batch)
+					return nil
+				}))
+				sizes = append(sizes, size)
+			}
+			assertEqual(t, ids, expected)
+			assertEqual(t, sizes, []int{3, 3, 4})
+		}
+	}
+
+	// bounded by the range of the cursor.
+	cursor := (&pageboy.Cursor{Limit: 10, After: "3"}).Paginate("ID").Order(ASC)
+	cursors, err := pageboy.Partition(db.Model(&cursorModel{}), cursor, 2)
+	assertNoError(t, err)
+	assertEqual(t, len(cursors), 2)
+	assertEqual(t, cursors[0].After, cursor.After)
+	assertEqual(t, string(cursors[0].Before), "7")
+	assertEqual(t, string(cursors[1].After), "6")
+	assertEqual(t, string(cursors[1].Before), "")
+
+	// fewer partitions than k.
+	cursors, err = pageboy.Partition(db.Model(&cursorModel{}), cursor, 100)
+	assertNoError(t, err)
+	assertEqual(t, len(cursors), 7)
+
+	_, err = pageboy.Partition(db, cursor, 2)
+	assertEqual(t, err, pageboy.ErrModelRequired)
+	_, err = pageboy.Partition(db.Model(&cursorModel{}), cursor, 0)
+	assertError(t, err)
+}
+
+func TestEachPartition(t *testing.T) {
+	db := openDB()
+	assertNoError(t, db.Migrator().DropTable(&cursorModel{}))
+	assertNoError(t, db.AutoMigrate(&cursorModel{}))
+
+	for i := 0; i < 10; i++ {
+		assertNoError(t, db.Create(&cursorModel{}).Error)
+	}
+
+	cursor := (&pageboy.Cursor{Limit: 2}).Paginate("ID").Order(ASC)
+	cursors, err := pageboy.Partition(db.Model(&cursorModel{}), cursor, 4)
+	assertNoError(t, err)
+
+	var mu sync.Mutex
+	var ids []uint
+	err = pageboy.EachPartition(context.Background(), db, cursors, func(batch []cursorModel) error {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, model := range batch {
+			ids = append(ids, model.ID)
+		}
+		return nil
+	})
+	assertNoError(t, err)
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	assertEqual(t, ids, []uint{1, 2, 3, 4, 5, 6, 7, 8, 9, 10})
+
+	errStop := errors.New("stop")
+	err = pageboy.EachPartition(context.Background(), db, cursors, func(batch []cursorModel) error {
+		return errStop
+	})
+	assertEqual(t, err, errStop)
+}
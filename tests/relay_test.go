@@ -0,0 +1,106 @@
+package pageboy_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/soranoba/pageboy/v4"
+	pbc "github.com/soranoba/pageboy/v4/core"
+)
+
+func TestFindConnection(t *testing.T) {
+	db := openDB()
+	assertNoError(t, db.Migrator().DropTable(&cursorModel{}))
+	assertNoError(t, db.AutoMigrate(&cursorModel{}))
+
+	for i := 0; i < 10; i++ {
+		assertNoError(t, db.Create(&cursorModel{}).Error)
+	}
+
+	intPtr := func(v int) *int { return &v }
+	find := func(order string, args pageboy.RelayArgs) ([]uint, []pbc.CursorString, pageboy.PageInfo) {
+		conn, err := pageboy.FindConnection[*cursorModel](db, pageboy.NewCursor().Paginate("ID").Order(order), args)
+		assertNoError(t, err)
+		var ids []uint
+		var cursors []pbc.CursorString
+		for _, edge := range conn.Edges {
+			ids = append(ids, edge.Node.ID)
+			cursors = append(cursors, edge.Cursor)
+		}
+		return ids, cursors, conn.PageInfo
+	}
+	cs := func(s pbc.CursorString) *pbc.CursorString { return &s }
+
+	ids, cursors, info := find(ASC, pageboy.RelayArgs{First: intPtr(3)})
+	assertEqual(t, ids, []uint{1, 2, 3})
+	assertEqual(t, cursors, []pbc.CursorString{"1", "2", "3"})
+	assertEqual(t, info, pageboy.PageInfo{HasNextPage: true, StartCursor: cs("1"), EndCursor: cs("3")})
+
+	ids, _, info = find(ASC, pageboy.RelayArgs{First: intPtr(3), After: "3"})
+	assertEqual(t, ids, []uint{4, 5, 6})
+	assertEqual(t, info, pageboy.PageInfo{HasNextPage: true, HasPreviousPage: true, StartCursor: cs("4"), EndCursor: cs("6")})
+
+	ids, _, info = find(ASC, pageboy.RelayArgs{First: intPtr(3), After: "8"})
+	assertEqual(t, ids, []uint{9, 10})
+	assertEqual(t, info, pageboy.PageInfo{HasPreviousPage: true, StartCursor: cs("9"), EndCursor: cs("10")})
+
+	ids, _, info = find(ASC, pageboy.RelayArgs{Last: intPtr(3)})
+	assertEqual(t, ids, []uint{8, 9, 10})
+	assertEqual(t, info, pageboy.PageInfo{HasPreviousPage: true, StartCursor: cs("8"), EndCursor: cs("10")})
+
+	ids, _, info = find(ASC, pageboy.RelayArgs{Last: intPtr(3), Before: "8"})
+	assertEqual(t, ids, []uint{5, 6, 7})
+	assertEqual(t, info, pageboy.PageInfo{HasNextPage: true, HasPreviousPage: true, StartCursor: cs("5"), EndCursor: cs("7")})
+
+	ids, _, info = find(ASC, pageboy.RelayArgs{Last: intPtr(3), Before: "3"})
+	assertEqual(t, ids, []uint{1, 2})
+	assertEqual(t, info, pageboy.PageInfo{HasNextPage: true, StartCursor: cs("1"), EndCursor: cs("2")})
+
+	ids, _, info = find(DESC, pageboy.RelayArgs{First: intPtr(3), After: "8"})
+	assertEqual(t, ids, []uint{7, 6, 5})
+	assertEqual(t, info, pageboy.PageInfo{HasNextPage: true, HasPreviousPage: true, StartCursor: cs("7"), EndCursor: cs("5")})
+
+	ids, _, info = find(DESC, pageboy.RelayArgs{Last: intPtr(2), Before: "2"})
+	assertEqual(t, ids, []uint{4, 3})
+	assertEqual(t, info, pageboy.PageInfo{HasNextPage: true, HasPreviousPage: true, StartCursor: cs("4"), EndCursor: cs("3")})
+
+	ids, _, info = find(DESC, pageboy.RelayArgs{First: intPtr(3), After: "5", Before: "2"})
+	assertEqual(t, ids, []uint{4, 3})
+	assertEqual(t, info, pageboy.PageInfo{HasPreviousPage: true, StartCursor: cs("4"), EndCursor: cs("3")})
+
+	ids, _, info = find(ASC, pageboy.RelayArgs{})
+	assertEqual(t, len(ids), 10)
+	assertEqual(t, info.HasNextPage, false)
+	assertEqual(t, info.HasPreviousPage, false)
+
+	_, err := pageboy.FindConnection[*cursorModel](db, pageboy.NewCursor().Paginate("ID").Order(ASC), pageboy.RelayArgs{First: intPtr(1), Last: intPtr(1)})
+	assertError(t, err)
+	_, err = pageboy.FindConnection[*cursorModel](db, pageboy.NewCursor().Paginate("ID").Order(ASC), pageboy.RelayArgs{First: intPtr(-1)})
+	assertError(t, err)
+
+	// the positions that do not have the values of the columns.
+	var validationErr *pageboy.ValidationError
+	_, err = pageboy.FindConnection[*cursorModel](db, pageboy.NewCursor().Paginate("ID").Order(ASC), pageboy.RelayArgs{After: "1_2"})
+	if assertEqual(t, errors.As(err, &validationErr), true) {
+		assertEqual(t, validationErr.Field, "After")
+	}
+	_, err = pageboy.FindConnection[*cursorModel](db, pageboy.NewCursor().Paginate("ID").Order(ASC), pageboy.RelayArgs{Last: intPtr(1), Before: "1_2"})
+	if assertEqual(t, errors.As(err, &validationErr), true) {
+		assertEqual(t, validationErr.Field, "Before")
+	}
+
+	// the tie-breaker accepts the positions made before and after appending the primary key.
+	cursor := pageboy.NewCursor().Paginate("Time").Order(DESC).TieBreaker(pageboy.TieBreakerAppend)
+	_, err = pageboy.FindConnection[*cursorModel](db, cursor, pageboy.RelayArgs{After: "1"})
+	assertNoError(t, err)
+	_, err = pageboy.FindConnection[*cursorModel](db, cursor, pageboy.RelayArgs{After: "1_2"})
+	assertNoError(t, err)
+	_, err = pageboy.FindConnection[*cursorModel](db, cursor, pageboy.RelayArgs{After: "1_2_3"})
+	if assertEqual(t, errors.As(err, &validationErr), true) {
+		assertEqual(t, validationErr.Field, "After")
+	}
+	_, err = pageboy.FindConnection[*cursorModel](db, cursor, pageboy.RelayArgs{Last: intPtr(1), Before: "1_2_3"})
+	if assertEqual(t, errors.As(err, &validationErr), true) {
+		assertEqual(t, validationErr.Field, "Before")
+	}
+}
@@ -0,0 +1,83 @@
+package pageboy_test
+
+import (
+	"testing"
+
+	"github.com/soranoba/pageboy/v4"
+	pbc "github.com/soranoba/pageboy/v4/core"
+	"gorm.io/gorm"
+)
+
+type scanChildModel struct {
+	gorm.Model
+	ParentID uint
+	Name     string
+}
+
+type scanDTO struct {
+	ID        uint
+	ChildName string
+}
+
+func TestScanWithCursor(t *testing.T) {
+	db := openDB()
+	assertNoError(t, db.Migrator().DropTable(&cursorModel{}, &scanChildModel{}))
+	assertNoError(t, db.AutoMigrate(&cursorModel{}, &scanChildModel{}))
+
+	for i := 0; i < 5; i++ {
+		model := &cursorModel{}
+		assertNoError(t, db.Create(model).Error)
+		assertNoError(t, db.Create(&scanChildModel{ParentID: model.ID, Name: "child"}).Error)
+	}
+
+	query := func() *gorm.DB {
+		return db.Model(&cursorModel{}).
+			Select("cursor_models.id AS id, scan_child_models.name AS child_name").
+			Joins("JOIN scan_child_models ON scan_child_models.parent_id = cursor_models.id")
+	}
+
+	var dtos []scanDTO
+	cursor := (&pageboy.Cursor{Limit: 2}).Paginate("ID").Order(ASC).Counts(pageboy.Counts{Total: pageboy.CountExact})
+	assertNoError(t, pageboy.Scan(query().Scopes(cursor.Scope()), &dtos).Error)
+	assertEqual(t, dtos, []scanDTO{{ID: 1, ChildName: "child"}, {ID: 2, ChildName: "child"}})
+	assertEqual(t, cursor.GetNextAfter(), pbc.CursorString("2"))
+	assertEqual(t, cursor.Summary().HasMore, true)
+	assertEqual(t, cursor.Summary().Total.Count, int64(5))
+
+	cursor.After = cursor.GetNextAfter()
+	assertNoError(t, pageboy.Scan(query().Scopes(cursor.Scope()), &dtos).Error)
+	assertEqual(t, len(dtos), 2)
+	assertEqual(t, dtos[0].ID, uint(3))
+
+	cursor.After = "4"
+	assertNoError(t, pageboy.Scan(query().Scopes(cursor.Scope()), &dtos).Error)
+	assertEqual(t, dtos, []scanDTO{{ID: 5, ChildName: "child"}})
+	assertEqual(t, cursor.Summary().HasMore, false)
+
+	cursor.After = "5"
+	assertNoError(t, pageboy.Scan(query().Scopes(cursor.Scope()), &dtos).Error)
+	assertEqual(t, len(dtos), 0)
+	assertEqual(t, cursor.GetNextAfter(), pbc.CursorString("5"))
+}
+
+func TestScanWithPager(t *testing.T) {
+	db := openDB()
+	assertNoError(t, db.Migrator().DropTable(&pagerModel{}))
+	assertNoError(t, db.AutoMigrate(&pagerModel{}))
+
+	for i := 0; i < 5; i++ {
+		assertNoError(t, db.Create(&pagerModel{}).Error)
+	}
+
+	var dtos []struct {
+		ID uint
+	}
+	pager := (&pageboy.Pager{Page: 2, PerPage: 2}).Paginate("ID").Order(ASC)
+	assertNoError(t, pageboy.Scan(db.Table("pager_models").Select("id").Scopes(pager.Scope()), &dtos).Error)
+	assertEqual(t, len(dtos), 2)
+	assertEqual(t, dtos[0].ID, uint(3))
+	summary := pager.Summary()
+	assertEqual(t, summary.TotalCount, int64(5))
+	assertEqual(t, summary.TotalPage, 3)
+	assertNotEqual(t, summary.NextAnchor, pbc.CursorString(""))
+}
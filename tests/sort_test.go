@@ -0,0 +1,104 @@
+package pageboy_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/soranoba/pageboy/v4"
+)
+
+func TestSorts(t *testing.T) {
+	sorts := pageboy.NewSorts("ID").
+		Add("created_at", pageboy.SortField{Column: "CreatedAt", Order: "DESC"}).
+		Add("name", pageboy.SortField{Column: "Name"}).
+		Add("time", pageboy.SortField{Column: "Time", Nulls: "NULLS LAST"}).
+		Add("id", pageboy.SortField{Column: "ID"}).
+		Default("-created_at")
+
+	parse := func(spec string) ([]string, []string) {
+		columns, orders, err := sorts.Parse(spec)
+		assertNoError(t, err)
+		return columns, orders
+	}
+
+	columns, orders := parse("-created_at,name")
+	assertEqual(t, columns, []string{"CreatedAt", "Name", "ID"})
+	assertEqual(t, orders, []string{"DESC", "ASC", "DESC"})
+
+	columns, orders = parse("created_at,+name")
+	assertEqual(t, columns, []string{"CreatedAt", "Name", "ID"})
+	assertEqual(t, orders, []string{"DESC", "ASC", "DESC"})
+
+	// `+` is decoded as a space.
+	columns, orders = parse(" created_at,-time")
+	assertEqual(t, columns, []string{"CreatedAt", "Time", "ID"})
+	assertEqual(t, orders, []string{"ASC", "DESC NULLS LAST", "ASC"})
+
+	// the whitespaces around the keys are ignored.
+	columns, orders = parse("-created_at, name")
+	assertEqual(t, columns, []string{"CreatedAt", "Name", "ID"})
+	assertEqual(t, orders, []string{"DESC", "ASC", "DESC"})
+
+	columns, orders = parse("created_at, -name")
+	assertEqual(t, columns, []string{"CreatedAt", "Name", "ID"})
+	assertEqual(t, orders, []string{"DESC", "DESC", "DESC"})
+
+	columns, orders = parse("name,-id")
+	assertEqual(t, columns, []string{"Name", "ID"})
+	assertEqual(t, orders, []string{"ASC", "DESC"})
+
+	columns, orders = parse("")
+	assertEqual(t, columns, []string{"CreatedAt", "ID"})
+	assertEqual(t, orders, []string{"DESC", "DESC"})
+
+	columns, orders, err := pageboy.NewSorts("ID").Parse("")
+	assertNoError(t, err)
+	assertEqual(t, columns, []string{"ID"})
+	assertEqual(t, orders, []string{"ASC"})
+
+	var validationErr *pageboy.ValidationError
+	_, _, err = sorts.Parse("password")
+	assertEqual(t, errors.As(err, &validationErr), true)
+	assertEqual(t, validationErr.Field, "sort")
+	_, _, err = sorts.Parse("name,-name")
+	assertEqual(t, errors.As(err, &validationErr), true)
+}
+
+func TestSorts_apply(t *testing.T) {
+	db := openDB()
+	assertNoError(t, db.Migrator().DropTable(&cursorModel{}))
+	assertNoError(t, db.AutoMigrate(&cursorModel{}))
+
+	for _, subID := range []uint{2, 1, 2, 3, 1} {
+		subID := subID
+		assertNoError(t, db.Create(&cursorModel{SubID: &subID}).Error)
+	}
+
+	sorts := pageboy.NewSorts("ID").Add("sub_id", pageboy.SortField{Column: "SubID"})
+
+	ids := func(models []cursorModel) []uint {
+		var ids []uint
+		for _, model := range models {
+			ids = append(ids, model.ID)
+		}
+		return ids
+	}
+
+	cursor := &pageboy.Cursor{Limit: 3}
+	assertNoError(t, sorts.ApplyCursor(cursor, "-sub_id"))
+
+	var models []cursorModel
+	assertNoError(t, db.Scopes(cursor.Scope()).Find(&models).Error)
+	assertEqual(t, ids(models), []uint{4, 3, 1})
+
+	cursor.Before = cursor.GetNextBefore()
+	assertNoError(t, db.Scopes(cursor.Scope()).Find(&models).Error)
+	assertEqual(t, ids(models), []uint{5, 2})
+
+	pager := &pageboy.Pager{Page: 1, PerPage: 3}
+	assertNoError(t, sorts.ApplyPager(pager, "sub_id"))
+	assertNoError(t, db.Scopes(pager.Scope()).Find(&models).Error)
+	assertEqual(t, ids(models), []uint{2, 5, 1})
+
+	assertError(t, sorts.ApplyCursor(cursor, "time"))
+}
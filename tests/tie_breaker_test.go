@@ -0,0 +1,102 @@
+package pageboy_test
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/soranoba/pageboy/v4"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type uniqueModel struct {
+	ID    uint `gorm:"primarykey"`
+	Code  int  `gorm:"unique"`
+	Group int  `gorm:"uniqueIndex:idx_unique_models_group_seq"`
+	Seq   int  `gorm:"uniqueIndex:idx_unique_models_group_seq"`
+	Time  time.Time
+}
+
+func TestCursorTieBreaker(t *testing.T) {
+	db := openDB()
+	assertNoError(t, db.Migrator().DropTable(&cursorModel{}))
+	assertNoError(t, db.AutoMigrate(&cursorModel{}))
+
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		assertNoError(t, db.Create(&cursorModel{Time: &now}).Error)
+	}
+
+	for _, order := range []string{ASC, DESC} {
+		var ids []uint
+		cursor := (&pageboy.Cursor{Limit: 2}).Paginate("Time").Order(order).TieBreaker(pageboy.TieBreakerAppend)
+		assertNoError(t, pageboy.Each(context.Background(), db, cursor, func(batch []cursorModel) error {
+			for _, model := range batch {
+				ids = append(ids, model.ID)
+			}
+			return nil
+		}))
+		if order == ASC {
+			assertEqual(t, ids, []uint{1, 2, 3, 4, 5})
+		} else {
+			assertEqual(t, ids, []uint{5, 4, 3, 2, 1})
+		}
+	}
+
+	// the cursor made before appending can be used.
+	var models []cursorModel
+	cursor := (&pageboy.Cursor{Limit: 2}).Paginate("Time").Order(ASC)
+	assertNoError(t, db.Scopes(cursor.Scope()).Find(&models).Error)
+	oldAfter := cursor.GetNextAfter()
+	assertEqual(t, strings.Count(string(oldAfter), "_"), 0)
+
+	cursor = (&pageboy.Cursor{Limit: 2, After: oldAfter}).Paginate("Time").Order(ASC).TieBreaker(pageboy.TieBreakerAppend)
+	assertNoError(t, db.Scopes(cursor.Scope()).Find(&models).Error)
+	assertEqual(t, len(models), 0)
+
+	// error
+	cursor = (&pageboy.Cursor{Limit: 2}).Paginate("Time").Order(ASC).TieBreaker(pageboy.TieBreakerError)
+	assertEqual(t, db.Scopes(cursor.Scope()).Find(&models).Error, pageboy.ErrNotUniqueColumns)
+
+	// warn
+	var buf bytes.Buffer
+	warnDB := db.Session(&gorm.Session{Logger: logger.New(log.New(&buf, "", 0), logger.Config{LogLevel: logger.Warn})})
+	cursor = (&pageboy.Cursor{Limit: 2}).Paginate("Time").Order(ASC).TieBreaker(pageboy.TieBreakerWarn)
+	assertNoError(t, warnDB.Scopes(cursor.Scope()).Find(&models).Error)
+	assertEqual(t, strings.Contains(buf.String(), "not unique"), true)
+	assertEqual(t, len(models), 2)
+}
+
+func TestCursorTieBreaker_unique(t *testing.T) {
+	db := openDB()
+	assertNoError(t, db.Migrator().DropTable(&uniqueModel{}))
+	assertNoError(t, db.AutoMigrate(&uniqueModel{}))
+
+	for i := 0; i < 3; i++ {
+		assertNoError(t, db.Create(&uniqueModel{Code: i, Group: i % 2, Seq: i}).Error)
+	}
+
+	for _, columns := range [][]string{
+		{"ID"},
+		{"Code"},
+		{"Group", "Seq"},
+		{"Time", "Seq", "Group"},
+	} {
+		var models []uniqueModel
+		orders := make([]string, len(columns))
+		for i := range orders {
+			orders[i] = ASC
+		}
+		cursor := (&pageboy.Cursor{Limit: 2}).Paginate(columns...).Order(orders...).TieBreaker(pageboy.TieBreakerError)
+		assertNoError(t, db.Scopes(cursor.Scope()).Find(&models).Error)
+		assertEqual(t, len(models), 2)
+	}
+
+	var models []uniqueModel
+	cursor := (&pageboy.Cursor{Limit: 2}).Paginate("Seq").Order(ASC).TieBreaker(pageboy.TieBreakerError)
+	assertEqual(t, db.Scopes(cursor.Scope()).Find(&models).Error, pageboy.ErrNotUniqueColumns)
+}
@@ -0,0 +1,113 @@
+package pageboy
+
+import (
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// ErrNotUniqueColumns is an error returned when the columns of the cursor are not unique.
+// See: TieBreakerError
+var ErrNotUniqueColumns = errors.New("columns of the cursor are not unique")
+
+// TieBreakerPolicy is how to handle the columns of the cursor that are not provably unique.
+// The columns are unique when they contain the primary keys or the fields of a unique index of the model.
+//
+// If the columns are not unique, records with equal values are skipped or duplicated across pages.
+type TieBreakerPolicy int
+
+const (
+	// TieBreakerIgnore does nothing.
+	TieBreakerIgnore TieBreakerPolicy = iota
+	// TieBreakerAppend appends the primary keys to the columns with the base order.
+	// Cursors made before appending can still be used.
+	TieBreakerAppend
+	// TieBreakerWarn logs a warning by the logger of GORM.
+	TieBreakerWarn
+	// TieBreakerError returns ErrNotUniqueColumns.
+	TieBreakerError
+)
+
+// withTieBreaker returns the cursor that the columns are unique by the policy.
+// If the columns are already unique, it returns the cursor itself.
+func (cursor *Cursor) withTieBreaker(db *gorm.DB) (*Cursor, error) {
+	s := db.Statement.Schema
+	if cursor.tieBreaker == TieBreakerIgnore || s == nil || isUniqueColumns(s, cursor.columns) {
+		return cursor, nil
+	}
+
+	switch cursor.tieBreaker {
+	case TieBreakerAppend:
+		if len(s.PrimaryFields) > 0 {
+			effective := cursor.clone()
+			effective.columns = append([]string{}, cursor.columns...)
+			effective.rawOrders = append([]string{}, cursor.rawOrders...)
+			// NOTE: the orders of columns that have no order are the base order.
+			for len(effective.rawOrders) < len(effective.columns) {
+				effective.rawOrders = append(effective.rawOrders, strings.ToUpper(string(cursor.baseOrder())))
+			}
+			for _, field := range s.PrimaryFields {
+				if !containsString(effective.columns, field.Name) {
+					effective.columns = append(effective.columns, field.Name)
+					effective.rawOrders = append(effective.rawOrders, strings.ToUpper(string(cursor.baseOrder())))
+				}
+			}
+			effective.orders, effective.nullsOrders = parseOrders(effective.rawOrders)
+			return effective, nil
+		}
+		fallthrough
+	case TieBreakerWarn:
+		db.Logger.Warn(db.Statement.Context, "pageboy: the columns %v of the cursor are not unique in %s", cursor.columns, s.Name)
+		return cursor, nil
+	default:
+		return nil, ErrNotUniqueColumns
+	}
+}
+
+// isUniqueColumns returns true if the columns contain the primary keys or the fields of a unique index.
+func isUniqueColumns(s *schema.Schema, columns []string) bool {
+	containsAll := func(fields []*schema.Field) bool {
+		if len(fields) == 0 {
+			return false
+		}
+		for _, field := range fields {
+			if !containsString(columns, field.Name) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if containsAll(s.PrimaryFields) {
+		return true
+	}
+	for _, field := range s.Fields {
+		if field.Unique && containsString(columns, field.Name) {
+			return true
+		}
+	}
+	for _, index := range s.ParseIndexes() {
+		if index.Class != "UNIQUE" {
+			continue
+		}
+		fields := make([]*schema.Field, len(index.Fields))
+		for i, option := range index.Fields {
+			fields[i] = option.Field
+		}
+		if containsAll(fields) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}